@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/hcp/fakehcp"
+	"github.com/hashicorp/go-hclog"
+)
+
+// stringList is a flag that can be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+func main() {
+	var (
+		addr                     string
+		clientID                 string
+		clientSecret             string
+		workloadIdentityProvider string
+		workloadIdentityToken    string
+		includes                 stringList
+		labels                   stringList
+	)
+	flag.StringVar(&addr, "addr", "127.0.0.1:8443", "address to serve the fake HCP on")
+	flag.StringVar(&clientID, "client-id", "", "the only client id accepted, any client is accepted when empty")
+	flag.StringVar(&clientSecret, "client-secret", "", "the secret of the client id")
+	flag.StringVar(&workloadIdentityProvider, "workload-identity-provider", "",
+		"resource name of the workload identity provider tokens are exchanged with, exchange is disabled when empty")
+	flag.StringVar(&workloadIdentityToken, "workload-identity-token", "", "the only workload identity token accepted")
+	flag.Var(&includes, "include", "metric filter regex of the telemetry config, may be repeated")
+	flag.Var(&labels, "label", "key=value label of the telemetry config, may be repeated")
+	flag.Parse()
+
+	logger := hclog.New(&hclog.LoggerOptions{Name: "fake-hcp"})
+
+	cfg := fakehcp.Config{
+		ClientID:                 clientID,
+		ClientSecret:             clientSecret,
+		WorkloadIdentityProvider: workloadIdentityProvider,
+		WorkloadIdentityToken:    workloadIdentityToken,
+		IncludeList:              includes,
+		Labels:                   make(map[string]string, len(labels)),
+	}
+	for _, label := range labels {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok {
+			logger.Error("labels must be key=value", "label", label)
+			os.Exit(1)
+		}
+		cfg.Labels[key] = value
+	}
+
+	server, err := fakehcp.Start(addr, cfg)
+	if err != nil {
+		logger.Error("failed to start the fake HCP", "error", err)
+		os.Exit(1)
+	}
+	defer server.Close()
+
+	env := server.Env()
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Println("# point the collector at the fake HCP with:")
+	for _, k := range keys {
+		fmt.Printf("export %s=%s\n", k, env[k])
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	reported := 0
+	for {
+		select {
+		case <-signals:
+			return
+		case <-ticker.C:
+			exports := server.Exports()
+			for _, e := range exports[reported:] {
+				logger.Info("received metrics", "resource_id", e.ResourceID,
+					"data_points", e.Metrics.DataPointCount())
+			}
+			reported = len(exports)
+		}
+	}
+}
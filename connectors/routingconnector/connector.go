@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package routingconnector
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// route is a Route resolved to the consumer feeding its pipelines.
+type route struct {
+	partition  string
+	namespace  string
+	attributes map[string]string
+	consumer   consumer.Metrics
+}
+
+// matches reports whether the resource attributes match every partition, namespace and attribute the route sets.
+func (r route) matches(attrs pcommon.Map) bool {
+	if !attrMatches(attrs, partitionKey, r.partition) || !attrMatches(attrs, namespaceKey, r.namespace) {
+		return false
+	}
+	for key, want := range r.attributes {
+		if !attrMatches(attrs, key, want) {
+			return false
+		}
+	}
+	return true
+}
+
+func attrMatches(attrs pcommon.Map, key, want string) bool {
+	if want == "" {
+		return true
+	}
+	v, ok := attrs.Get(key)
+	return ok && v.AsString() == want
+}
+
+type router struct {
+	component.StartFunc
+	component.ShutdownFunc
+
+	logger *zap.Logger
+	routes []route
+	// defaultConsumer receives the metrics no route matches, it is nil when they are dropped.
+	defaultConsumer consumer.Metrics
+}
+
+var _ connector.Metrics = (*router)(nil)
+
+func newRouter(cfg *Config, set connector.CreateSettings, metricsRouter connector.MetricsRouter) (*router, error) {
+	r := &router{
+		logger: set.Logger,
+		routes: make([]route, 0, len(cfg.Routes)),
+	}
+
+	for _, cr := range cfg.Routes {
+		c, err := metricsRouter.Consumer(cr.Pipelines...)
+		if err != nil {
+			return nil, err
+		}
+		r.routes = append(r.routes, route{
+			partition:  cr.Partition,
+			namespace:  cr.Namespace,
+			attributes: cr.Attributes,
+			consumer:   c,
+		})
+	}
+
+	if len(cfg.DefaultPipelines) > 0 {
+		c, err := metricsRouter.Consumer(cfg.DefaultPipelines...)
+		if err != nil {
+			return nil, err
+		}
+		r.defaultConsumer = c
+	}
+	return r, nil
+}
+
+func (r *router) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeMetrics splits the metrics by the route matching their resource and sends each part to its pipelines. The
+// metrics are only copied when their resources match different routes, otherwise they are passed on as they are.
+func (r *router) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	rms := md.ResourceMetrics()
+	routes := make([]int, rms.Len())
+	split := false
+	for i := 0; i < rms.Len(); i++ {
+		routes[i] = r.match(rms.At(i).Resource().Attributes())
+		split = split || routes[i] != routes[0]
+	}
+	if !split {
+		if rms.Len() == 0 {
+			return nil
+		}
+		return r.consume(ctx, routes[0], md)
+	}
+
+	// the last batch holds the metrics of the default route.
+	batches := make([]pmetric.Metrics, len(r.routes)+1)
+	for i, idx := range routes {
+		if batches[idx] == (pmetric.Metrics{}) {
+			batches[idx] = pmetric.NewMetrics()
+		}
+		rms.At(i).CopyTo(batches[idx].ResourceMetrics().AppendEmpty())
+	}
+
+	var errs error
+	for idx, batch := range batches {
+		if batch == (pmetric.Metrics{}) {
+			continue
+		}
+		errs = multierr.Append(errs, r.consume(ctx, idx, batch))
+	}
+	return errs
+}
+
+// consume sends the metrics to the pipelines of the route at idx, or drops them when idx is the default route and
+// there is no default pipeline.
+func (r *router) consume(ctx context.Context, idx int, md pmetric.Metrics) error {
+	c := r.defaultConsumer
+	if idx < len(r.routes) {
+		c = r.routes[idx].consumer
+	}
+	if c == nil {
+		r.logger.Debug("Dropping metrics that match no route", zap.Int("data_points", md.DataPointCount()))
+		return nil
+	}
+	return c.ConsumeMetrics(ctx, md)
+}
+
+// match returns the index of the first route matching the resource attributes, or len(r.routes) when none does.
+func (r *router) match(attrs pcommon.Map) int {
+	for i, rt := range r.routes {
+		if rt.matches(attrs) {
+			return i
+		}
+	}
+	return len(r.routes)
+}
@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package routingconnector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const nodeIDKey = "node.id"
+
+var (
+	teamAID    = component.NewIDWithName(component.DataTypeMetrics, "team-a")
+	paymentsID = component.NewIDWithName(component.DataTypeMetrics, "payments")
+	defaultID  = component.NewIDWithName(component.DataTypeMetrics, "default")
+
+	teamARoute    = Route{Partition: "team-a", Pipelines: []component.ID{teamAID}}
+	paymentsRoute = Route{Partition: "team-b", Namespace: "payments", Pipelines: []component.ID{paymentsID}}
+)
+
+// proxyMetrics creates metrics with one resource per proxy, each reporting a single gauge.
+func proxyMetrics(resources ...map[string]string) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	for _, attrs := range resources {
+		rm := md.ResourceMetrics().AppendEmpty()
+		for k, v := range attrs {
+			rm.Resource().Attributes().PutStr(k, v)
+		}
+		m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("cluster.upstream_rq_active")
+		m.SetEmptyGauge().DataPoints().AppendEmpty().SetIntValue(1)
+	}
+	return md
+}
+
+// resourceNodes returns the node.id of every resource the sink received.
+func resourceNodes(sink *consumertest.MetricsSink) []string {
+	var nodes []string
+	for _, md := range sink.AllMetrics() {
+		for i := 0; i < md.ResourceMetrics().Len(); i++ {
+			v, _ := md.ResourceMetrics().At(i).Resource().Attributes().Get(nodeIDKey)
+			nodes = append(nodes, v.Str())
+		}
+	}
+	return nodes
+}
+
+func TestRouter_ConsumeMetrics(t *testing.T) {
+	sinks := map[component.ID]*consumertest.MetricsSink{
+		teamAID:    new(consumertest.MetricsSink),
+		paymentsID: new(consumertest.MetricsSink),
+		defaultID:  new(consumertest.MetricsSink),
+	}
+	metricsRouter := connectortest.NewMetricsRouter(
+		connectortest.WithMetricsSink(teamAID, sinks[teamAID]),
+		connectortest.WithMetricsSink(paymentsID, sinks[paymentsID]),
+		connectortest.WithMetricsSink(defaultID, sinks[defaultID]),
+	)
+	cfg := &Config{
+		Routes:           []Route{teamARoute, paymentsRoute},
+		DefaultPipelines: []component.ID{defaultID},
+	}
+	r, err := newRouter(cfg, connectortest.NewNopCreateSettings(), metricsRouter)
+	must.NoError(t, err)
+
+	md := proxyMetrics(
+		map[string]string{nodeIDKey: "a-1", "partition": "team-a", "namespace": "payments"},
+		map[string]string{nodeIDKey: "b-1", "partition": "team-b", "namespace": "payments"},
+		map[string]string{nodeIDKey: "b-2", "partition": "team-b", "namespace": "default"},
+		map[string]string{nodeIDKey: "oss", "envoy.cluster": "web"},
+		map[string]string{nodeIDKey: "a-2", "partition": "team-a"},
+	)
+	must.NoError(t, r.ConsumeMetrics(context.Background(), md))
+
+	// the first matching route wins and each route receives a single batch.
+	must.Eq(t, []string{"a-1", "a-2"}, resourceNodes(sinks[teamAID]))
+	must.Len(t, 1, sinks[teamAID].AllMetrics())
+	must.Eq(t, []string{"b-1"}, resourceNodes(sinks[paymentsID]))
+	must.Eq(t, []string{"b-2", "oss"}, resourceNodes(sinks[defaultID]))
+}
+
+func TestRouter_SingleRoute(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	metricsRouter := connectortest.NewMetricsRouter(connectortest.WithMetricsSink(teamAID, sink))
+	r, err := newRouter(&Config{Routes: []Route{teamARoute}}, connectortest.NewNopCreateSettings(), metricsRouter)
+	must.NoError(t, err)
+
+	md := proxyMetrics(
+		map[string]string{nodeIDKey: "a-1", "partition": "team-a"},
+		map[string]string{nodeIDKey: "a-2", "partition": "team-a"},
+	)
+	must.NoError(t, r.ConsumeMetrics(context.Background(), md))
+
+	// metrics matching a single route are passed on without being copied.
+	must.Len(t, 1, sink.AllMetrics())
+	must.True(t, sink.AllMetrics()[0] == md)
+	must.NoError(t, r.ConsumeMetrics(context.Background(), pmetric.NewMetrics()))
+	must.Len(t, 1, sink.AllMetrics())
+}
+
+func TestRouter_Attributes(t *testing.T) {
+	eastID := component.NewIDWithName(component.DataTypeMetrics, "east")
+	sink := new(consumertest.MetricsSink)
+	metricsRouter := connectortest.NewMetricsRouter(connectortest.WithMetricsSink(eastID, sink))
+	cfg := &Config{Routes: []Route{{
+		Partition:  "team-a",
+		Attributes: map[string]string{"hcp": "east", "region": "us-east-1"},
+		Pipelines:  []component.ID{eastID},
+	}}}
+	r, err := newRouter(cfg, connectortest.NewNopCreateSettings(), metricsRouter)
+	must.NoError(t, err)
+
+	md := proxyMetrics(
+		map[string]string{nodeIDKey: "a-1", "partition": "team-a", "hcp": "east", "region": "us-east-1"},
+		map[string]string{nodeIDKey: "a-2", "partition": "team-a", "hcp": "east"},
+		map[string]string{nodeIDKey: "b-1", "partition": "team-b", "hcp": "east", "region": "us-east-1"},
+		map[string]string{nodeIDKey: "a-3", "partition": "team-a", "hcp": "west", "region": "us-east-1"},
+	)
+	must.NoError(t, r.ConsumeMetrics(context.Background(), md))
+
+	// every attribute and the partition must match.
+	must.Eq(t, []string{"a-1"}, resourceNodes(sink))
+}
+
+func TestRouter_DropUnmatched(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	metricsRouter := connectortest.NewMetricsRouter(connectortest.WithMetricsSink(teamAID, sink))
+	r, err := newRouter(&Config{Routes: []Route{teamARoute}}, connectortest.NewNopCreateSettings(), metricsRouter)
+	must.NoError(t, err)
+
+	md := proxyMetrics(
+		map[string]string{nodeIDKey: "b-1", "partition": "team-b"},
+	)
+	must.NoError(t, r.ConsumeMetrics(context.Background(), md))
+	must.Len(t, 0, sink.AllMetrics())
+}
+
+func TestRouter_UnknownPipeline(t *testing.T) {
+	metricsRouter := connectortest.NewMetricsRouter(connectortest.WithMetricsSink(defaultID,
+		new(consumertest.MetricsSink)))
+	_, err := newRouter(&Config{Routes: []Route{teamARoute}}, connectortest.NewNopCreateSettings(), metricsRouter)
+	must.Error(t, err)
+}
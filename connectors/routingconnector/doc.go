@@ -0,0 +1,11 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package routingconnector implements a connector that routes metrics to pipelines by the Consul admin partition
+// and namespace of the envoy proxy that reported them, the partition and namespace resource attributes set by the
+// envoy receiver, or by other resource attributes, like the ones set from the node metadata.
+//
+// The routes are matched in order and the metrics of a resource are sent to the pipelines of the first route
+// matching it. A route matches when every partition, namespace and attribute it sets equals the resource attribute. The
+// metrics of the resources no route matches are sent to the default pipelines, or dropped when there are none.
+package routingconnector
@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package routingconnector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+const (
+	// ID is the identifier for the connector.
+	ID = "routing"
+
+	partitionKey = "partition"
+	namespaceKey = "namespace"
+)
+
+// Config is the configuration for the routing connector.
+type Config struct {
+	// Routes are matched in order against the resource of the metrics.
+	Routes []Route `mapstructure:"routes"`
+
+	// DefaultPipelines receive the metrics no route matches. They are dropped when it is empty.
+	DefaultPipelines []component.ID `mapstructure:"default_pipelines"`
+}
+
+// Route sends the metrics of a Consul admin partition, namespace, or namespace within a partition, or the metrics
+// with some resource attribute values, to pipelines.
+type Route struct {
+	// Partition matches the partition resource attribute when set.
+	Partition string `mapstructure:"partition"`
+
+	// Namespace matches the namespace resource attribute when set.
+	Namespace string `mapstructure:"namespace"`
+
+	// Attributes match the values of other resource attributes, like the ones set from node metadata.
+	Attributes map[string]string `mapstructure:"attributes,omitempty"`
+
+	// Pipelines receive the metrics matching the route.
+	Pipelines []component.ID `mapstructure:"pipelines"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that every route matches on a partition, namespace or attribute and has pipelines.
+func (c *Config) Validate() error {
+	if len(c.Routes) == 0 {
+		return errors.New("routes must not be empty")
+	}
+	for i, r := range c.Routes {
+		if r.Partition == "" && r.Namespace == "" && len(r.Attributes) == 0 {
+			return fmt.Errorf("route %d must match a partition, a namespace or attributes", i)
+		}
+		if len(r.Pipelines) == 0 {
+			return fmt.Errorf("route %d must have pipelines", i)
+		}
+	}
+	return nil
+}
+
+// NewFactory creates a new routing connector factory.
+func NewFactory() connector.Factory {
+	return connector.NewFactory(
+		ID,
+		CreateDefaultConfig,
+		connector.WithMetricsToMetrics(createMetricsToMetrics, component.StabilityLevelDevelopment),
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for the connector.
+func CreateDefaultConfig() component.Config {
+	return &Config{}
+}
+
+func createMetricsToMetrics(
+	_ context.Context,
+	set connector.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (connector.Metrics, error) {
+	router, ok := nextConsumer.(connector.MetricsRouter)
+	if !ok {
+		return nil, errors.New("expected the next consumer to be a metrics router")
+	}
+
+	return newRouter(cfg.(*Config), set, router)
+}
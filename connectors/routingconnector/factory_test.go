@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package routingconnector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shoenig/test"
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/connector/connectortest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	must.NotNil(t, cfg, must.Sprint("failed to create default config"))
+	must.NoError(t, componenttest.CheckConfigStruct(cfg))
+}
+
+func TestConfigValidate(t *testing.T) {
+	pipelines := []component.ID{component.NewIDWithName(component.DataTypeMetrics, "team-a")}
+	for name, tc := range map[string]struct {
+		cfg     *Config
+		wantErr bool
+	}{
+		"Valid": {
+			cfg: &Config{Routes: []Route{{Partition: "team-a", Pipelines: pipelines}}},
+		},
+		"ValidAttributes": {
+			cfg: &Config{Routes: []Route{{Attributes: map[string]string{"hcp": "east"}, Pipelines: pipelines}}},
+		},
+		"NoRoutes": {
+			cfg:     &Config{DefaultPipelines: pipelines},
+			wantErr: true,
+		},
+		"MatchesEverything": {
+			cfg:     &Config{Routes: []Route{{Pipelines: pipelines}}},
+			wantErr: true,
+		},
+		"NoPipelines": {
+			cfg:     &Config{Routes: []Route{{Namespace: "payments"}}},
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				test.Error(t, err)
+				return
+			}
+			test.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateConnector(t *testing.T) {
+	id := component.NewIDWithName(component.DataTypeMetrics, "team-a")
+	factory := NewFactory()
+	cfg := &Config{Routes: []Route{{Partition: "team-a", Pipelines: []component.ID{id}}}}
+	router := connectortest.NewMetricsRouter(connectortest.WithMetricsSink(id, new(consumertest.MetricsSink)))
+
+	c, err := factory.CreateMetricsToMetrics(context.Background(), connectortest.NewNopCreateSettings(), cfg, router.(consumer.Metrics))
+	must.NoError(t, err)
+	must.NotNil(t, c)
+	must.False(t, c.Capabilities().MutatesData)
+	must.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	test.NoError(t, c.Shutdown(context.Background()))
+}
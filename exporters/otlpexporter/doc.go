@@ -0,0 +1,11 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package otlpexporter implements the otlp exporter of the collector: the metrics of the upstream otlp exporter,
+// sent over gRPC connections that go through a proxy.
+//
+// The upstream exporter dials its connection with the default gRPC dialer, which only reads the proxy from the
+// environment of the process. This exporter dials through the configured proxy instead, with an HTTP CONNECT
+// tunnel, and trusts the proxy CA in addition to the roots of its TLS settings. It takes the configuration of the
+// upstream exporter.
+package otlpexporter
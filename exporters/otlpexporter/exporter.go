@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otlpexporter
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type metricsExporter struct {
+	config    *Config
+	settings  component.TelemetrySettings
+	userAgent string
+
+	clientConn  *grpc.ClientConn
+	client      pmetricotlp.GRPCClient
+	metadata    metadata.MD
+	callOptions []grpc.CallOption
+}
+
+func newMetricsExporter(cfg *Config, set exporter.CreateSettings) *metricsExporter {
+	return &metricsExporter{
+		config:   cfg,
+		settings: set.TelemetrySettings,
+		userAgent: fmt.Sprintf("%s/%s (%s/%s)",
+			set.BuildInfo.Description, set.BuildInfo.Version, runtime.GOOS, runtime.GOARCH),
+	}
+}
+
+// start dials the connection, through the proxy when there is one. The connection is made when the exporter starts
+// because the auth extensions it may use are only available then.
+func (e *metricsExporter) start(ctx context.Context, host component.Host) error {
+	opts := []grpc.DialOption{grpc.WithUserAgent(e.userAgent)}
+	if e.config.Proxy.Enabled() {
+		creds, err := e.transportCredentials()
+		if err != nil {
+			return err
+		}
+		// the options are applied after the ones of the settings, replacing their credentials.
+		opts = append(opts, grpc.WithContextDialer(e.config.Proxy.DialContext), grpc.WithTransportCredentials(creds))
+	}
+
+	conn, err := e.config.GRPCClientSettings.ToClientConn(ctx, host, e.settings, opts...)
+	if err != nil {
+		return err
+	}
+	e.clientConn = conn
+	e.client = pmetricotlp.NewGRPCClient(conn)
+
+	headers := map[string]string{}
+	for k, v := range e.config.Headers {
+		headers[k] = string(v)
+	}
+	e.metadata = metadata.New(headers)
+	e.callOptions = []grpc.CallOption{grpc.WaitForReady(e.config.WaitForReady)}
+	return nil
+}
+
+// transportCredentials returns the credentials of the TLS settings of the exporter, trusting the proxy CA.
+func (e *metricsExporter) transportCredentials() (credentials.TransportCredentials, error) {
+	tlsConfig, err := e.config.TLSSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		if !strings.HasPrefix(e.config.Endpoint, "https://") {
+			return insecure.NewCredentials(), nil
+		}
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	tlsConfig, err = e.config.Proxy.TLSConfig(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func (e *metricsExporter) shutdown(context.Context) error {
+	if e.clientConn != nil {
+		return e.clientConn.Close()
+	}
+	return nil
+}
+
+func (e *metricsExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) error {
+	if e.metadata.Len() > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, e.metadata)
+	}
+	resp, err := e.client.Export(ctx, pmetricotlp.NewExportRequestFromMetrics(md), e.callOptions...)
+	if err := processError(err); err != nil {
+		return err
+	}
+	partialSuccess := resp.PartialSuccess()
+	if partialSuccess.ErrorMessage() != "" || partialSuccess.RejectedDataPoints() != 0 {
+		return consumererror.NewPermanent(fmt.Errorf("OTLP partial success: %q (%d rejected)",
+			partialSuccess.ErrorMessage(), partialSuccess.RejectedDataPoints()))
+	}
+	return nil
+}
+
+// processError returns the error of an export: permanent unless its status code is retryable, and throttled when
+// the server asked to wait before retrying.
+func processError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st := status.Convert(err)
+	if st.Code() == codes.OK {
+		return nil
+	}
+
+	retryInfo := getRetryInfo(st)
+	if !shouldRetry(st.Code(), retryInfo) {
+		return consumererror.NewPermanent(err)
+	}
+	if throttle := getThrottleDuration(retryInfo); throttle != 0 {
+		return exporterhelper.NewThrottleRetry(err, throttle)
+	}
+	return err
+}
+
+func shouldRetry(code codes.Code, retryInfo *errdetails.RetryInfo) bool {
+	switch code {
+	case codes.Canceled,
+		codes.DeadlineExceeded,
+		codes.Aborted,
+		codes.OutOfRange,
+		codes.Unavailable,
+		codes.DataLoss:
+		return true
+	case codes.ResourceExhausted:
+		// the server can only recover from the exhaustion when it says when to retry.
+		return retryInfo != nil
+	}
+	return false
+}
+
+func getRetryInfo(st *status.Status) *errdetails.RetryInfo {
+	for _, detail := range st.Details() {
+		if t, ok := detail.(*errdetails.RetryInfo); ok {
+			return t
+		}
+	}
+	return nil
+}
+
+func getThrottleDuration(t *errdetails.RetryInfo) time.Duration {
+	if t == nil || t.RetryDelay == nil {
+		return 0
+	}
+	if d := t.RetryDelay.AsDuration(); d > 0 {
+		return d
+	}
+	return 0
+}
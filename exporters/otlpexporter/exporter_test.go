@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otlpexporter
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+type metricsServer struct {
+	pmetricotlp.UnimplementedGRPCServer
+	received chan pmetric.Metrics
+}
+
+func (s *metricsServer) Export(_ context.Context, req pmetricotlp.ExportRequest) (pmetricotlp.ExportResponse,
+	error,
+) {
+	s.received <- req.Metrics()
+	return pmetricotlp.NewExportResponse(), nil
+}
+
+func TestMetricsExporter_Proxy(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	srv := &metricsServer{received: make(chan pmetric.Metrics, 1)}
+	grpcServer := grpc.NewServer()
+	pmetricotlp.RegisterGRPCServer(grpcServer, srv)
+	go func() { _ = grpcServer.Serve(lis) }()
+	t.Cleanup(grpcServer.Stop)
+
+	tunneled := make(chan string, 1)
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tunneled <- r.Host
+		backend, err := net.Dial("tcp", lis.Addr().String())
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer backend.Close()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() { _, _ = io.Copy(backend, conn) }()
+		_, _ = io.Copy(conn, backend)
+	}))
+	t.Cleanup(proxySrv.Close)
+
+	cfg := CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "otlp.example.com:4317"
+	cfg.TLSSetting = configtls.TLSClientSetting{Insecure: true}
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.Enabled = false
+	cfg.Proxy = &proxy.Config{URL: proxySrv.URL}
+
+	exp, err := NewFactory().CreateMetricsExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	must.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { must.NoError(t, exp.Shutdown(context.Background())) })
+
+	// the metrics go through a tunnel the proxy opens to the endpoint.
+	metrics := pmetric.NewMetrics()
+	metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("m")
+	must.NoError(t, exp.ConsumeMetrics(context.Background(), metrics))
+	must.Eq(t, "otlp.example.com:4317", <-tunneled)
+	select {
+	case received := <-srv.received:
+		must.Eq(t, "m", received.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+	case <-time.After(5 * time.Second):
+		t.Fatal("the metrics were not received")
+	}
+}
+
+func TestProcessError(t *testing.T) {
+	throttled, err := status.New(codes.ResourceExhausted, "slow down").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	must.NoError(t, err)
+
+	must.NoError(t, processError(nil))
+	must.True(t, consumererror.IsPermanent(processError(status.Error(codes.InvalidArgument, "bad"))))
+	must.True(t, consumererror.IsPermanent(processError(status.Error(codes.ResourceExhausted, "full"))))
+
+	err = processError(status.Error(codes.Unavailable, "down"))
+	must.Error(t, err)
+	must.False(t, consumererror.IsPermanent(err))
+
+	err = processError(throttled.Err())
+	must.Error(t, err)
+	must.False(t, consumererror.IsPermanent(err))
+}
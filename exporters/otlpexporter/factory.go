@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otlpexporter
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+// ID is the identifier for the exporter.
+const ID = "otlp"
+
+// Config is the configuration of the upstream otlp exporter with the proxy its connections go through.
+type Config struct {
+	otlpexporter.Config `mapstructure:",squash"`
+
+	// Proxy, when set, is the proxy the connections of the exporter go through.
+	Proxy *proxy.Config `mapstructure:"proxy,omitempty"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that the configuration is usable.
+func (c *Config) Validate() error {
+	if c.Endpoint == "" {
+		return errors.New("endpoint must be specified")
+	}
+	return c.Config.Validate()
+}
+
+// NewFactory creates a new otlp exporter factory.
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		ID,
+		CreateDefaultConfig,
+		exporter.WithMetrics(createMetricsExporter, component.StabilityLevelDevelopment),
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for the exporter, the one of the upstream exporter.
+func CreateDefaultConfig() component.Config {
+	return &Config{
+		Config: *otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config),
+	}
+}
+
+func createMetricsExporter(ctx context.Context, set exporter.CreateSettings, cfg component.Config,
+) (exporter.Metrics, error) {
+	oCfg := cfg.(*Config)
+	exp := newMetricsExporter(oCfg, set)
+	return exporterhelper.NewMetricsExporter(ctx, set, cfg,
+		exp.pushMetrics,
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
+		exporterhelper.WithTimeout(oCfg.TimeoutSettings),
+		exporterhelper.WithRetry(oCfg.RetrySettings),
+		exporterhelper.WithQueue(oCfg.QueueSettings),
+		exporterhelper.WithStart(exp.start),
+		exporterhelper.WithShutdown(exp.shutdown),
+	)
+}
@@ -0,0 +1,10 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package otlphttpexporter implements the otlphttp exporter of the collector: the upstream otlphttp exporter with a
+// proxy its connections go through.
+//
+// The upstream exporter builds its transport when it starts and has no proxy setting of its own, so the proxy is set
+// by an auth extension the exporter is given in place of its own. The transport of the exporter is the base of its
+// auth round tripper, which then delegates to the authenticator the exporter is configured with.
+package otlphttpexporter
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otlphttpexporter
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/extension/auth"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+// proxyAuthType is the type of the auth extension setting the proxy, which is only known to its exporter.
+const proxyAuthType = "proxy"
+
+// metricsExporter is the upstream exporter, started with the auth extension setting the proxy.
+type metricsExporter struct {
+	exporter.Metrics
+	auth *proxyAuth
+}
+
+func (e *metricsExporter) Start(ctx context.Context, host component.Host) error {
+	return e.Metrics.Start(ctx, &proxyHost{Host: host, auth: e.auth})
+}
+
+// proxyHost is the host of the exporter with the auth extension setting the proxy.
+type proxyHost struct {
+	component.Host
+	auth *proxyAuth
+}
+
+func (h *proxyHost) GetExtensions() map[component.ID]component.Component {
+	extensions := maps.Clone(h.Host.GetExtensions())
+	if extensions == nil {
+		extensions = map[component.ID]component.Component{}
+	}
+	extensions[h.auth.id] = auth.NewClient(auth.WithClientRoundTripper(func(base http.RoundTripper) (
+		http.RoundTripper, error,
+	) {
+		return h.auth.roundTripper(base, h.Host)
+	}))
+	return extensions
+}
+
+// proxyAuth sets the proxy on the transport of the exporter, then authenticates its requests with the next
+// authenticator, the one the exporter is configured with.
+type proxyAuth struct {
+	id    component.ID
+	proxy *proxy.Config
+	next  *configauth.Authentication
+}
+
+func (a *proxyAuth) roundTripper(base http.RoundTripper, host component.Host) (http.RoundTripper, error) {
+	transport, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("failed to set the proxy on a %T transport", base)
+	}
+	if err := a.proxy.Apply(transport); err != nil {
+		return nil, err
+	}
+	if a.next == nil {
+		return transport, nil
+	}
+
+	client, err := a.next.GetClientAuthenticator(host.GetExtensions())
+	if err != nil {
+		return nil, err
+	}
+	return client.RoundTripper(transport)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otlphttpexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/extension/auth"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+type authHost struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h *authHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+func TestMetricsExporter_Proxy(t *testing.T) {
+	var proxiedHost, authorization string
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		authorization = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(proxySrv.Close)
+
+	bearerID := component.NewID("bearertokenauth")
+	host := &authHost{
+		Host: componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{
+			bearerID: auth.NewClient(auth.WithClientRoundTripper(func(base http.RoundTripper) (http.RoundTripper,
+				error,
+			) {
+				return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+					r.Header.Set("Authorization", "Bearer token")
+					return base.RoundTrip(r)
+				}), nil
+			})),
+		},
+	}
+
+	cfg := CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "http://otlp.example.com"
+	cfg.Auth = &configauth.Authentication{AuthenticatorID: bearerID}
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.Enabled = false
+	cfg.Proxy = &proxy.Config{URL: proxySrv.URL}
+
+	exp, err := NewFactory().CreateMetricsExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	must.NoError(t, exp.Start(context.Background(), host))
+	t.Cleanup(func() { must.NoError(t, exp.Shutdown(context.Background())) })
+
+	// the metrics go through the proxy, authenticated by the exporter's own authenticator.
+	metrics := pmetric.NewMetrics()
+	metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("m")
+	must.NoError(t, exp.ConsumeMetrics(context.Background(), metrics))
+	must.Eq(t, "otlp.example.com", proxiedHost)
+	must.Eq(t, "Bearer token", authorization)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
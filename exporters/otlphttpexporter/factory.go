@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otlphttpexporter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+// ID is the identifier for the exporter.
+const ID = "otlphttp"
+
+// Config is the configuration of the upstream otlphttp exporter with the proxy its connections go through.
+type Config struct {
+	otlphttpexporter.Config `mapstructure:",squash"`
+
+	// Proxy, when set, is the proxy the connections of the exporter go through.
+	Proxy *proxy.Config `mapstructure:"proxy,omitempty"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// NewFactory creates a new otlphttp exporter factory.
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		ID,
+		CreateDefaultConfig,
+		exporter.WithMetrics(createMetricsExporter, component.StabilityLevelDevelopment),
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for the exporter, the one of the upstream exporter.
+func CreateDefaultConfig() component.Config {
+	return &Config{
+		Config: *otlphttpexporter.NewFactory().CreateDefaultConfig().(*otlphttpexporter.Config),
+	}
+}
+
+func createMetricsExporter(ctx context.Context, set exporter.CreateSettings, cfg component.Config,
+) (exporter.Metrics, error) {
+	oCfg := cfg.(*Config)
+	if !oCfg.Proxy.Enabled() {
+		return otlphttpexporter.NewFactory().CreateMetricsExporter(ctx, set, &oCfg.Config)
+	}
+
+	httpCfg := oCfg.Config
+	p := &proxyAuth{
+		id:    component.NewIDWithName(proxyAuthType, set.ID.String()),
+		proxy: oCfg.Proxy,
+		next:  httpCfg.Auth,
+	}
+	httpCfg.Auth = &configauth.Authentication{AuthenticatorID: p.id}
+
+	exp, err := otlphttpexporter.NewFactory().CreateMetricsExporter(ctx, set, &httpCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsExporter{Metrics: exp, auth: p}, nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otlphttpexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	must.NotNil(t, cfg, must.Sprint("failed to create default config"))
+	must.NoError(t, componenttest.CheckConfigStruct(cfg))
+}
+
+func TestCreateMetricsExporter(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "https://otlp.example.com"
+
+	exp, err := factory.CreateMetricsExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	_, wrapped := exp.(*metricsExporter)
+	must.False(t, wrapped, must.Sprint("the exporter without a proxy is wrapped"))
+
+	cfg.Proxy = &proxy.Config{URL: "http://proxy:3128"}
+	exp, err = factory.CreateMetricsExporter(context.Background(), exportertest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	must.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	must.NoError(t, exp.Shutdown(context.Background()))
+}
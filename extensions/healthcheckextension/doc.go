@@ -0,0 +1,10 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package healthcheckextension implements an extension that serves HTTP liveness and readiness probes
+// for orchestrators like Kubernetes and Nomad.
+//
+// The liveness endpoint reports healthy as soon as the extension has started. The readiness endpoint reports
+// healthy once every pipeline has started, the envoy receiver is accepting connections, every additional Check
+// passes and, when configured, no exporter has been failing to send metrics for longer than the configured age.
+package healthcheckextension
@@ -0,0 +1,297 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package healthcheckextension
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.uber.org/zap"
+)
+
+const (
+	probeTimeout = 2 * time.Second
+
+	// sentMetricPointsName is the internal collector metric counting the metric points an exporter sent.
+	sentMetricPointsName = "otelcol_exporter_sent_metric_points"
+	// failedMetricPointsName is the internal collector metric counting the metric points an exporter failed to
+	// send, once its retries are exhausted.
+	failedMetricPointsName = "otelcol_exporter_send_failed_metric_points"
+	exporterLabel          = "exporter"
+)
+
+type healthCheck struct {
+	cfg    *Config
+	logger *zap.Logger
+	checks []Check
+
+	server     *http.Server
+	httpClient *http.Client
+	shutdownCh chan struct{}
+
+	// ready is set once every pipeline has started.
+	ready atomic.Bool
+
+	// exportMu guards the exporter progress tracking.
+	exportMu  sync.Mutex
+	started   time.Time
+	exporters map[string]*exporterProgress
+}
+
+// exporterProgress tracks the metric points sent and failed by an exporter.
+type exporterProgress struct {
+	exportCounts
+	// lastExport is when the exporter last sent metric points, or when the extension was created.
+	lastExport time.Time
+	// failing is set when the exporter failed to send metric points since its last export.
+	failing bool
+}
+
+// exportCounts are the metric points sent and failed by an exporter since the collector started.
+type exportCounts struct {
+	sent   float64
+	failed float64
+}
+
+var _ extension.Extension = (*healthCheck)(nil)
+var _ extension.PipelineWatcher = (*healthCheck)(nil)
+
+// status is the body returned by the health check endpoints.
+type status struct {
+	Status string   `json:"status"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+func newHealthCheck(cfg *Config, set extension.CreateSettings, checks []Check) *healthCheck {
+	return &healthCheck{
+		cfg:        cfg,
+		logger:     set.Logger.Named("healthcheckextension"),
+		checks:     checks,
+		httpClient: &http.Client{Timeout: probeTimeout},
+		// the exporters are given ExportMaxAge from startup to send their first batch.
+		started:   time.Now(),
+		exporters: make(map[string]*exporterProgress),
+	}
+}
+
+func (h *healthCheck) Start(_ context.Context, host component.Host) error {
+	listener, err := net.Listen("tcp", h.cfg.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to bind health check endpoint %s: %w", h.cfg.Endpoint, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivenessPath, h.handleLiveness)
+	mux.HandleFunc(ReadinessPath, h.handleReadiness)
+	h.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: probeTimeout,
+	}
+
+	h.logger.Info("Starting health check server", zap.String("endpoint", h.cfg.Endpoint))
+
+	h.shutdownCh = make(chan struct{})
+	go func() {
+		defer close(h.shutdownCh)
+		if err := h.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			host.ReportFatalError(err)
+		}
+	}()
+
+	return nil
+}
+
+func (h *healthCheck) Shutdown(ctx context.Context) error {
+	if h.server == nil {
+		return nil
+	}
+
+	err := h.server.Shutdown(ctx)
+	if h.shutdownCh != nil {
+		<-h.shutdownCh
+	}
+	return err
+}
+
+// Ready is called by the collector once all pipelines are built and receivers are started.
+func (h *healthCheck) Ready() error {
+	h.ready.Store(true)
+	return nil
+}
+
+// NotReady is called by the collector before receivers are stopped.
+func (h *healthCheck) NotReady() error {
+	h.ready.Store(false)
+	return nil
+}
+
+func (h *healthCheck) handleLiveness(w http.ResponseWriter, _ *http.Request) {
+	writeStatus(w, nil)
+}
+
+func (h *healthCheck) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), probeTimeout)
+	defer cancel()
+
+	errs := h.readinessErrors(ctx)
+	if len(errs) > 0 {
+		h.logger.Debug("collector is not ready", zap.Errors("errors", errs))
+	}
+	writeStatus(w, errs)
+}
+
+// readinessErrors runs every readiness check and returns the errors of those that failed.
+func (h *healthCheck) readinessErrors(ctx context.Context) []error {
+	var errs []error
+	if !h.ready.Load() {
+		errs = append(errs, errors.New("pipelines are not running"))
+	}
+
+	if h.cfg.EnvoyEndpoint != "" {
+		if err := h.checkEnvoyListener(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if h.cfg.CheckExporters {
+		errs = append(errs, h.checkExporters(ctx)...)
+	}
+
+	for _, check := range h.checks {
+		if err := check(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func (h *healthCheck) checkEnvoyListener(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", h.cfg.EnvoyEndpoint)
+	if err != nil {
+		return fmt.Errorf("envoy receiver is not serving: %w", err)
+	}
+	return conn.Close()
+}
+
+// checkExporters reads the metric points sent and failed by every exporter from the collector's internal
+// metrics. An exporter is failing when it failed to send metric points and has not sent any within the configured
+// ExportMaxAge. Exporters that have nothing to send are not failing.
+func (h *healthCheck) checkExporters(ctx context.Context) []error {
+	counts, err := h.exportCounts(ctx)
+	if err != nil {
+		return []error{fmt.Errorf("failed to read the metrics sent by the exporters: %w", err)}
+	}
+
+	h.exportMu.Lock()
+	defer h.exportMu.Unlock()
+
+	exporters := make([]string, 0, len(counts))
+	for exporter := range counts {
+		exporters = append(exporters, exporter)
+	}
+	sort.Strings(exporters)
+
+	var errs []error
+	now := time.Now()
+	for _, exporter := range exporters {
+		progress, ok := h.exporters[exporter]
+		if !ok {
+			progress = &exporterProgress{lastExport: h.started}
+			h.exporters[exporter] = progress
+		}
+
+		count := counts[exporter]
+		if count.sent > progress.sent {
+			progress.lastExport = now
+			progress.failing = false
+		} else if count.failed > progress.failed {
+			progress.failing = true
+		}
+		progress.exportCounts = count
+
+		if since := now.Sub(progress.lastExport); progress.failing && since > h.cfg.ExportMaxAge {
+			errs = append(errs, fmt.Errorf("exporter %s is failing and has not sent metrics in %s", exporter,
+				since.Truncate(time.Second)))
+		}
+	}
+	return errs
+}
+
+// exportCounts returns the metric points sent and failed by each exporter.
+func (h *healthCheck) exportCounts(ctx context.Context) (map[string]exportCounts, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.cfg.MetricsEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]exportCounts)
+	for name, family := range families {
+		// counters may or may not be exposed with the _total suffix depending on how they were registered.
+		name = strings.TrimSuffix(name, "_total")
+		if name != sentMetricPointsName && name != failedMetricPointsName {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() != exporterLabel {
+					continue
+				}
+				count := counts[label.GetValue()]
+				if name == sentMetricPointsName {
+					count.sent += metric.GetCounter().GetValue()
+				} else {
+					count.failed += metric.GetCounter().GetValue()
+				}
+				counts[label.GetValue()] = count
+			}
+		}
+	}
+	return counts, nil
+}
+
+func writeStatus(w http.ResponseWriter, errs []error) {
+	s := status{Status: "ok"}
+	code := http.StatusOK
+	if len(errs) > 0 {
+		s.Status = "unavailable"
+		code = http.StatusServiceUnavailable
+		for _, err := range errs {
+			s.Errors = append(s.Errors, err.Error())
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(s)
+}
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package healthcheckextension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/portal"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func TestHealthCheck_Liveness(t *testing.T) {
+	endpoint := startHealthCheck(t, &Config{})
+
+	resp, err := http.Get(fmt.Sprintf("http://%s%s", endpoint, LivenessPath))
+	must.NoError(t, err)
+	defer resp.Body.Close()
+	must.Eq(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHealthCheck_Readiness(t *testing.T) {
+	envoyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	t.Cleanup(func() { _ = envoyListener.Close() })
+
+	for name, tc := range map[string]struct {
+		cfg         *Config
+		checks      []Check
+		notReady    bool
+		expect      int
+		sentCount   float64
+		failedCount float64
+	}{
+		"Ready": {
+			cfg:    &Config{EnvoyEndpoint: envoyListener.Addr().String()},
+			expect: http.StatusOK,
+		},
+		"PipelinesNotReady": {
+			cfg:      &Config{},
+			notReady: true,
+			expect:   http.StatusServiceUnavailable,
+		},
+		"EnvoyNotServing": {
+			cfg:    &Config{EnvoyEndpoint: fmt.Sprintf("127.0.0.1:%d", portal.New(t).One())},
+			expect: http.StatusServiceUnavailable,
+		},
+		"CheckFailed": {
+			cfg: &Config{},
+			checks: []Check{func(context.Context) error {
+				return errors.New("boom")
+			}},
+			expect: http.StatusServiceUnavailable,
+		},
+		"ExporterIdle": {
+			cfg:    &Config{CheckExporters: true, ExportMaxAge: time.Nanosecond},
+			expect: http.StatusOK,
+		},
+		"ExporterFailingWithinMaxAge": {
+			cfg:         &Config{CheckExporters: true, ExportMaxAge: time.Minute},
+			failedCount: 5,
+			expect:      http.StatusOK,
+		},
+		"ExporterFailing": {
+			cfg:         &Config{CheckExporters: true, ExportMaxAge: time.Nanosecond},
+			failedCount: 5,
+			expect:      http.StatusServiceUnavailable,
+		},
+		"ExporterSent": {
+			cfg:         &Config{CheckExporters: true, ExportMaxAge: time.Nanosecond},
+			sentCount:   10,
+			failedCount: 5,
+			expect:      http.StatusOK,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.cfg.CheckExporters {
+				tc.cfg.MetricsEndpoint = metricsServer(t, "otlphttp/hcp", tc.sentCount, tc.failedCount)
+			}
+
+			ext := newHealthCheck(tc.cfg, extensiontest.NewNopCreateSettings(), tc.checks)
+			if !tc.notReady {
+				must.NoError(t, ext.Ready())
+			}
+			time.Sleep(time.Millisecond)
+
+			rec := httptest.NewRecorder()
+			ext.handleReadiness(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+			must.Eq(t, tc.expect, rec.Code, must.Sprint(rec.Body.String()))
+		})
+	}
+}
+
+func TestHealthCheck_ExporterProgress(t *testing.T) {
+	var sent, failed atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, "# TYPE %s_total counter\n", sentMetricPointsName)
+		fmt.Fprintf(w, "%s_total{exporter=%q} %d\n", sentMetricPointsName, "otlphttp/hcp", sent.Load())
+		fmt.Fprintf(w, "%s_total{exporter=%q} 100\n", sentMetricPointsName, "otlphttp")
+		fmt.Fprintf(w, "# TYPE %s_total counter\n", failedMetricPointsName)
+		fmt.Fprintf(w, "%s_total{exporter=%q} %d\n", failedMetricPointsName, "otlphttp/hcp", failed.Load())
+	}))
+	t.Cleanup(srv.Close)
+
+	ext := newHealthCheck(&Config{
+		CheckExporters:  true,
+		MetricsEndpoint: srv.URL,
+		ExportMaxAge:    time.Nanosecond,
+	}, extensiontest.NewNopCreateSettings(), nil)
+
+	ctx := context.Background()
+	must.SliceEmpty(t, ext.checkExporters(ctx))
+
+	// failing to send makes the exporter unhealthy until it sends again.
+	failed.Store(3)
+	errs := ext.checkExporters(ctx)
+	must.SliceLen(t, 1, errs)
+	must.ErrorContains(t, errs[0], "exporter otlphttp/hcp is failing")
+	must.SliceLen(t, 1, ext.checkExporters(ctx))
+
+	sent.Store(5)
+	must.SliceEmpty(t, ext.checkExporters(ctx))
+	must.SliceEmpty(t, ext.checkExporters(ctx))
+	must.Eq(t, 5, ext.exporters["otlphttp/hcp"].sent)
+
+	failed.Store(4)
+	must.SliceLen(t, 1, ext.checkExporters(ctx))
+}
+
+func startHealthCheck(t *testing.T, cfg *Config) string {
+	t.Helper()
+
+	cfg.Endpoint = fmt.Sprintf("127.0.0.1:%d", portal.New(t).One())
+	ext, err := NewFactory().CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	must.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { must.NoError(t, ext.Shutdown(context.Background())) })
+	return cfg.Endpoint
+}
+
+func metricsServer(t *testing.T, exporter string, sent, failed float64) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if sent > 0 {
+			fmt.Fprintf(w, "# TYPE %s counter\n", sentMetricPointsName)
+			fmt.Fprintf(w, "%s{exporter=%q} %g\n", sentMetricPointsName, exporter, sent)
+		}
+		if failed > 0 {
+			fmt.Fprintf(w, "# TYPE %s counter\n", failedMetricPointsName)
+			fmt.Fprintf(w, "%s{exporter=%q} %g\n", failedMetricPointsName, exporter, failed)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package healthcheckextension
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+)
+
+const (
+	// ID is the identifier for the extension.
+	ID = "health_check"
+
+	// DefaultEndpoint is the default address the health check server listens on. It only accepts probes from the
+	// host itself, so that the collector's state isn't exposed on the network by default. Probes from outside the
+	// host, like the kubelet's, need the endpoint set to an address listening on every interface, like 0.0.0.0:13133.
+	DefaultEndpoint = "localhost:13133"
+
+	// LivenessPath is the path of the liveness probe.
+	LivenessPath = "/health/live"
+
+	// ReadinessPath is the path of the readiness probe.
+	ReadinessPath = "/health/ready"
+
+	defaultExportMaxAge = 5 * time.Minute
+)
+
+// Check is an additional readiness check. A non-nil error marks the collector as not ready.
+type Check func(ctx context.Context) error
+
+// Config is the configuration for the health check extension.
+type Config struct {
+	// Endpoint is the address the health check HTTP server listens on.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// EnvoyEndpoint is the address of the envoy receiver gRPC listener. The collector is not ready
+	// until a connection to it can be established. (optional)
+	EnvoyEndpoint string `mapstructure:"envoy_endpoint"`
+
+	// CheckExporters makes the collector not ready while one of its exporters fails to send metrics and has not
+	// sent any within ExportMaxAge. Every exporter is checked. (optional)
+	CheckExporters bool `mapstructure:"check_exporters"`
+
+	// MetricsEndpoint is the URL of the collector's internal prometheus metrics. It is required when
+	// CheckExporters is set, since the metric points sent and failed by the exporters are read from it.
+	MetricsEndpoint string `mapstructure:"metrics_endpoint"`
+
+	// ExportMaxAge is how long a failing exporter may go without sending metrics before the collector is
+	// no longer ready.
+	ExportMaxAge time.Duration `mapstructure:"export_max_age"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that the configuration is usable.
+func (c *Config) Validate() error {
+	if c.Endpoint == "" {
+		return errors.New("endpoint must be specified")
+	}
+	if c.CheckExporters && c.MetricsEndpoint == "" {
+		return errors.New("metrics_endpoint must be specified when the exporters are checked")
+	}
+	return nil
+}
+
+// NewFactory creates a new health check extension factory. The provided checks are evaluated in addition
+// to the configured checks every time readiness is probed.
+func NewFactory(checks ...Check) extension.Factory {
+	return extension.NewFactory(
+		ID,
+		CreateDefaultConfig,
+		func(_ context.Context, set extension.CreateSettings, cfg component.Config) (extension.Extension, error) {
+			return newHealthCheck(cfg.(*Config), set, checks), nil
+		},
+		component.StabilityLevelDevelopment,
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for the extension.
+func CreateDefaultConfig() component.Config {
+	return &Config{
+		Endpoint:     DefaultEndpoint,
+		ExportMaxAge: defaultExportMaxAge,
+	}
+}
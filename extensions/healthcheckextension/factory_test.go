@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package healthcheckextension
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shoenig/test"
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	must.NotNil(t, cfg, must.Sprint("failed to create default config"))
+	must.NoError(t, componenttest.CheckConfigStruct(cfg))
+	must.NoError(t, cfg.(*Config).Validate())
+}
+
+func TestConfigValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg     *Config
+		wantErr bool
+	}{
+		"Valid": {
+			cfg: &Config{Endpoint: "localhost:0"},
+		},
+		"MissingEndpoint": {
+			cfg:     &Config{},
+			wantErr: true,
+		},
+		"CheckExportersWithoutMetricsEndpoint": {
+			cfg:     &Config{Endpoint: "localhost:0", CheckExporters: true},
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				test.Error(t, err)
+				return
+			}
+			test.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateExtension(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "127.0.0.1:0"
+
+	ext, err := factory.CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	must.NotNil(t, ext)
+	must.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	test.NoError(t, ext.Shutdown(context.Background()))
+}
@@ -0,0 +1,9 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package oauth2clientextension implements a client authenticator extension that authenticates exporters with the
+// access tokens of an OAuth2 client credentials grant, like the HCP service principal credentials.
+//
+// It replaces the contrib oauth2client extension, whose token client can't go through a proxy of its own. The
+// tokens are requested through the configured proxy and reused until they expire.
+package oauth2clientextension
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oauth2clientextension
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/auth"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/oauth"
+)
+
+type clientCredentials struct {
+	tokenSource oauth2.TokenSource
+}
+
+func newClientCredentials(cfg *Config, _ extension.CreateSettings) (auth.Client, error) {
+	tlsConfig, err := cfg.TLSSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS configuration: %w", err)
+	}
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSClientConfig = tlsConfig
+	if err := cfg.Proxy.Apply(transport); err != nil {
+		return nil, err
+	}
+
+	grant := clientcredentials.Config{
+		ClientID:       cfg.ClientID,
+		ClientSecret:   string(cfg.ClientSecret),
+		TokenURL:       cfg.TokenURL,
+		Scopes:         cfg.Scopes,
+		EndpointParams: cfg.EndpointParams,
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+	})
+	c := &clientCredentials{
+		tokenSource: &tokenSource{
+			TokenSource: grant.TokenSource(ctx),
+			tokenURL:    cfg.TokenURL,
+		},
+	}
+
+	return auth.NewClient(
+		auth.WithClientRoundTripper(c.roundTripper),
+		auth.WithClientPerRPCCredentials(c.perRPCCredentials),
+	), nil
+}
+
+// roundTripper authenticates HTTP requests with the access token.
+func (c *clientCredentials) roundTripper(base http.RoundTripper) (http.RoundTripper, error) {
+	return &oauth2.Transport{
+		Source: c.tokenSource,
+		Base:   base,
+	}, nil
+}
+
+// perRPCCredentials authenticates gRPC requests with the access token.
+func (c *clientCredentials) perRPCCredentials() (credentials.PerRPCCredentials, error) {
+	return oauth.TokenSource{TokenSource: c.tokenSource}, nil
+}
+
+// tokenSource names the token endpoint in the errors of the token requests.
+type tokenSource struct {
+	oauth2.TokenSource
+	tokenURL string
+}
+
+func (s *tokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.TokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a token from %s: %w", s.tokenURL, err)
+	}
+	return token, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oauth2clientextension
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/extension/auth"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/hcp/fakehcp"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+func TestRoundTripper(t *testing.T) {
+	server, err := fakehcp.Start("127.0.0.1:0", fakehcp.Config{ClientID: "cid", ClientSecret: "csec"})
+	must.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	var authorization string
+	backend := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(backend.Close)
+
+	cfg := CreateDefaultConfig().(*Config)
+	cfg.ClientID = "cid"
+	cfg.ClientSecret = "csec"
+	cfg.TokenURL = "https://" + server.Address() + "/oauth2/token"
+	cfg.TLSSetting.InsecureSkipVerify = true
+
+	ext, err := NewFactory().CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+
+	rt, err := ext.(auth.Client).RoundTripper(http.DefaultTransport)
+	must.NoError(t, err)
+	resp, err := (&http.Client{Transport: rt}).Get(backend.URL)
+	must.NoError(t, err)
+	must.NoError(t, resp.Body.Close())
+	must.StrHasPrefix(t, "Bearer ", authorization)
+
+	// rejected credentials fail the request.
+	cfg.ClientSecret = "wrong"
+	ext, err = NewFactory().CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	rt, err = ext.(auth.Client).RoundTripper(http.DefaultTransport)
+	must.NoError(t, err)
+	_, err = (&http.Client{Transport: rt}).Get(backend.URL)
+	must.ErrorContains(t, err, "failed to get a token from "+cfg.TokenURL)
+}
+
+func TestRoundTripper_Proxy(t *testing.T) {
+	var tunneled string
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tunneled = r.Host
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(proxySrv.Close)
+
+	cfg := CreateDefaultConfig().(*Config)
+	cfg.ClientID = "cid"
+	cfg.ClientSecret = "csec"
+	cfg.TokenURL = "https://auth.idp.hashicorp.com/oauth2/token"
+	cfg.Proxy = &proxy.Config{URL: proxySrv.URL}
+
+	ext, err := NewFactory().CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	rt, err := ext.(auth.Client).RoundTripper(http.DefaultTransport)
+	must.NoError(t, err)
+
+	// the token is requested through the proxy, the request it authenticates is sent by its own transport.
+	_, err = (&http.Client{Transport: rt}).Get("http://127.0.0.1:1")
+	must.Error(t, err)
+	must.Eq(t, "auth.idp.hashicorp.com:443", tunneled)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oauth2clientextension
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/extension"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+// ID is the identifier for the extension.
+const ID = "oauth2client"
+
+// Config is the configuration for the oauth2client extension.
+type Config struct {
+	// ClientID is the application's ID.
+	ClientID string `mapstructure:"client_id"`
+
+	// ClientSecret is the application's secret.
+	ClientSecret configopaque.String `mapstructure:"client_secret"`
+
+	// EndpointParams specifies additional parameters for requests to the token endpoint.
+	EndpointParams url.Values `mapstructure:"endpoint_params"`
+
+	// TokenURL is the token endpoint of the authorization server.
+	TokenURL string `mapstructure:"token_url"`
+
+	// Scopes optionally specifies a list of requested permission scopes.
+	Scopes []string `mapstructure:"scopes,omitempty"`
+
+	// TLSSetting is the TLS configuration of the client connecting to the TokenURL.
+	TLSSetting configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// Timeout is the time limit of the token requests.
+	Timeout time.Duration `mapstructure:"timeout,omitempty"`
+
+	// Proxy, when set, is the proxy the connections to the TokenURL go through.
+	Proxy *proxy.Config `mapstructure:"proxy,omitempty"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that the configuration is usable.
+func (c *Config) Validate() error {
+	if c.ClientID == "" {
+		return errors.New("client_id must be specified")
+	}
+	if c.ClientSecret == "" {
+		return errors.New("client_secret must be specified")
+	}
+	if c.TokenURL == "" {
+		return errors.New("token_url must be specified")
+	}
+	return nil
+}
+
+// NewFactory creates a new oauth2client extension factory.
+func NewFactory() extension.Factory {
+	return extension.NewFactory(
+		ID,
+		CreateDefaultConfig,
+		func(_ context.Context, set extension.CreateSettings, cfg component.Config) (extension.Extension, error) {
+			return newClientCredentials(cfg.(*Config), set)
+		},
+		component.StabilityLevelDevelopment,
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for the extension.
+func CreateDefaultConfig() component.Config {
+	return &Config{}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package oauth2clientextension
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shoenig/test"
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/auth"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	must.NotNil(t, cfg, must.Sprint("failed to create default config"))
+	must.NoError(t, componenttest.CheckConfigStruct(cfg))
+}
+
+func TestConfigValidate(t *testing.T) {
+	const tokenURL = "https://auth.idp.hashicorp.com/oauth2/token"
+	for name, tc := range map[string]struct {
+		cfg     *Config
+		wantErr bool
+	}{
+		"Valid": {
+			cfg: &Config{ClientID: "cid", ClientSecret: "csec", TokenURL: tokenURL},
+		},
+		"MissingClientID": {
+			cfg:     &Config{ClientSecret: "csec", TokenURL: tokenURL},
+			wantErr: true,
+		},
+		"MissingClientSecret": {
+			cfg:     &Config{ClientID: "cid", TokenURL: tokenURL},
+			wantErr: true,
+		},
+		"MissingTokenURL": {
+			cfg:     &Config{ClientID: "cid", ClientSecret: "csec"},
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				test.Error(t, err)
+				return
+			}
+			test.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateExtension(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ClientID = "cid"
+	cfg.ClientSecret = "csec"
+	cfg.TokenURL = "https://auth.idp.hashicorp.com/oauth2/token"
+
+	ext, err := factory.CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	must.NotNil(t, ext)
+
+	_, ok := ext.(auth.Client)
+	must.True(t, ok, must.Sprint("the extension is not a client authenticator"))
+	must.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	must.NoError(t, ext.Shutdown(context.Background()))
+}
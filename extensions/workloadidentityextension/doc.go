@@ -0,0 +1,9 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package workloadidentityextension implements a client authenticator extension that authenticates exporters with
+// HCP by exchanging a workload identity token, like a Kubernetes projected service account token or a Nomad
+// workload identity, for HCP access tokens. It replaces long-lived service principal secrets on every node.
+//
+// The token file is read on every exchange and the access tokens are reused until they expire.
+package workloadidentityextension
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package workloadidentityextension
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/auth"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/oauth"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
+)
+
+type workloadIdentity struct {
+	tokenSource oauth2.TokenSource
+}
+
+func newWorkloadIdentity(cfg *Config, _ extension.CreateSettings) (auth.Client, error) {
+	tlsConfig, err := cfg.TLSSetting.LoadTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the TLS configuration: %w", err)
+	}
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSClientConfig = tlsConfig
+	if err := cfg.Proxy.Apply(transport); err != nil {
+		return nil, err
+	}
+
+	identity := hcp.WorkloadIdentity{
+		ProviderResourceName: cfg.ProviderResourceName,
+		TokenFile:            cfg.TokenFile,
+	}
+	w := &workloadIdentity{
+		tokenSource: identity.TokenSource(cfg.Endpoint, &http.Client{Transport: transport}),
+	}
+
+	return auth.NewClient(
+		auth.WithClientRoundTripper(w.roundTripper),
+		auth.WithClientPerRPCCredentials(w.perRPCCredentials),
+	), nil
+}
+
+// roundTripper authenticates HTTP requests with the exchanged access token.
+func (w *workloadIdentity) roundTripper(base http.RoundTripper) (http.RoundTripper, error) {
+	return &oauth2.Transport{
+		Source: w.tokenSource,
+		Base:   base,
+	}, nil
+}
+
+// perRPCCredentials authenticates gRPC requests with the exchanged access token.
+func (w *workloadIdentity) perRPCCredentials() (credentials.PerRPCCredentials, error) {
+	return oauth.TokenSource{TokenSource: w.tokenSource}, nil
+}
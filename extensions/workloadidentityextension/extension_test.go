@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package workloadidentityextension
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/extension/auth"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/hcp/fakehcp"
+)
+
+func TestRoundTripper(t *testing.T) {
+	const provider = "iam/project/p/service-principal/sp/workload-identity-provider/wip"
+	server, err := fakehcp.Start("127.0.0.1:0", fakehcp.Config{
+		WorkloadIdentityProvider: provider,
+		WorkloadIdentityToken:    "jwt",
+	})
+	must.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	var authorization string
+	backend := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	t.Cleanup(backend.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	must.NoError(t, os.WriteFile(tokenFile, []byte("jwt"), 0o600))
+
+	cfg := CreateDefaultConfig().(*Config)
+	cfg.ProviderResourceName = provider
+	cfg.TokenFile = tokenFile
+	cfg.Endpoint = "https://" + server.Address()
+	cfg.TLSSetting.InsecureSkipVerify = true
+
+	ext, err := NewFactory().CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+
+	rt, err := ext.(auth.Client).RoundTripper(http.DefaultTransport)
+	must.NoError(t, err)
+	client := &http.Client{Transport: rt}
+
+	resp, err := client.Get(backend.URL)
+	must.NoError(t, err)
+	must.NoError(t, resp.Body.Close())
+	must.StrHasPrefix(t, "Bearer ", authorization)
+
+	// a token that cannot be read fails the request.
+	cfg.TokenFile = filepath.Join(t.TempDir(), "missing")
+	ext, err = NewFactory().CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	rt, err = ext.(auth.Client).RoundTripper(http.DefaultTransport)
+	must.NoError(t, err)
+	_, err = (&http.Client{Transport: rt}).Get(backend.URL)
+	must.ErrorContains(t, err, "failed to read the workload identity token")
+}
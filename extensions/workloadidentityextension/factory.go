@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package workloadidentityextension
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/extension"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+const (
+	// ID is the identifier for the extension.
+	ID = "workload_identity"
+
+	// DefaultEndpoint is the HCP API the workload identity token is exchanged with.
+	DefaultEndpoint = "https://api.cloud.hashicorp.com"
+)
+
+// Config is the configuration for the workload identity extension.
+type Config struct {
+	// ProviderResourceName is the resource name of the HCP workload identity provider, like
+	// iam/project/<project id>/service-principal/<name>/workload-identity-provider/<name>.
+	ProviderResourceName string `mapstructure:"provider_resource_name"`
+
+	// TokenFile is the file the workload identity token is read from.
+	TokenFile string `mapstructure:"token_file"`
+
+	// Endpoint is the URL of the HCP API the token is exchanged with.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// TLSSetting is the TLS configuration of the client connecting to the Endpoint.
+	TLSSetting configtls.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// Proxy, when set, is the proxy the connections to the Endpoint go through.
+	Proxy *proxy.Config `mapstructure:"proxy,omitempty"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that the configuration is usable.
+func (c *Config) Validate() error {
+	if c.ProviderResourceName == "" {
+		return errors.New("provider_resource_name must be specified")
+	}
+	if c.TokenFile == "" {
+		return errors.New("token_file must be specified")
+	}
+	if c.Endpoint == "" {
+		return errors.New("endpoint must be specified")
+	}
+	return nil
+}
+
+// NewFactory creates a new workload identity extension factory.
+func NewFactory() extension.Factory {
+	return extension.NewFactory(
+		ID,
+		CreateDefaultConfig,
+		func(_ context.Context, set extension.CreateSettings, cfg component.Config) (extension.Extension, error) {
+			return newWorkloadIdentity(cfg.(*Config), set)
+		},
+		component.StabilityLevelDevelopment,
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for the extension.
+func CreateDefaultConfig() component.Config {
+	return &Config{
+		Endpoint: DefaultEndpoint,
+	}
+}
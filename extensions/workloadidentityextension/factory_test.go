@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package workloadidentityextension
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shoenig/test"
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/auth"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	must.NotNil(t, cfg, must.Sprint("failed to create default config"))
+	must.NoError(t, componenttest.CheckConfigStruct(cfg))
+	must.Eq(t, DefaultEndpoint, cfg.(*Config).Endpoint)
+}
+
+func TestConfigValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg     *Config
+		wantErr bool
+	}{
+		"Valid": {
+			cfg: &Config{ProviderResourceName: "wip", TokenFile: "/var/run/token", Endpoint: DefaultEndpoint},
+		},
+		"MissingProvider": {
+			cfg:     &Config{TokenFile: "/var/run/token", Endpoint: DefaultEndpoint},
+			wantErr: true,
+		},
+		"MissingTokenFile": {
+			cfg:     &Config{ProviderResourceName: "wip", Endpoint: DefaultEndpoint},
+			wantErr: true,
+		},
+		"MissingEndpoint": {
+			cfg:     &Config{ProviderResourceName: "wip", TokenFile: "/var/run/token"},
+			wantErr: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				test.Error(t, err)
+				return
+			}
+			test.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateExtension(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ProviderResourceName = "wip"
+	cfg.TokenFile = "/var/run/token"
+
+	ext, err := factory.CreateExtension(context.Background(), extensiontest.NewNopCreateSettings(), cfg)
+	must.NoError(t, err)
+	must.NotNil(t, ext)
+
+	_, ok := ext.(auth.Client)
+	must.True(t, ok, must.Sprint("the extension is not a client authenticator"))
+	must.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+	must.NoError(t, ext.Shutdown(context.Background()))
+}
@@ -27,6 +27,17 @@ Usage: consul-telemetry-collector agent [options]
 	Starts the telemetry-collector and runs until an interrupt is received. The
 	collector can forward all metrics to an otlphttp endpoint or to the Hashicorp
 	cloud platform.
+
+	Adding a debug block to the configuration file enables the following
+	debugging endpoints. They listen on localhost unless pprof_endpoint or
+	zpages_endpoint are set in the debug block:
+
+	  http://localhost:1777/debug/pprof/       Go runtime profiles (pprof)
+	  http://localhost:55679/debug/servicez    Collector service overview (zpages)
+	  http://localhost:55679/debug/pipelinez   Pipeline components (zpages)
+	  http://localhost:55679/debug/extensionz  Running extensions (zpages)
+	  http://localhost:55679/debug/featurez    Feature gates (zpages)
+	  http://localhost:55679/debug/tracez      Sampled spans (zpages)
 `
 )
 
@@ -58,6 +69,8 @@ func NewAgentCmd(ui cli.Ui) (*Command, error) {
 	c.flags.StringVar(&c.flagConfig.Cloud.ClientSecret, HCPClientSecretOpt, "", fmt.Sprintf("HCP Service Principal Client Secret Environment variable %s", "HCP_CLIENT_SECRET"))
 	c.flags.StringVar(&c.flagConfig.Cloud.ResourceID, HCPResourceIDOpt, "", fmt.Sprintf("HCP Resource ID Environment variable %s", "HCP_RESOURCE_ID"))
 	c.flags.StringVar(&c.flagConfig.HTTPCollectorEndpoint, COOtelHTTPEndpointOpt, "", fmt.Sprintf("OTLP HTTP endpoint to forward telemetry to Environment variable %s", "CO_OTEL_HTTP_ENDPOINT"))
+	c.flags.StringVar(&c.flagConfig.LogLevel, COOLogLevelOpt, "", fmt.Sprintf("Log level: trace, debug, info, warn or error. Defaults to info. Environment variable %s", COOLogLevel))
+	c.flags.BoolVar(&c.flagConfig.LogJSON, COOLogJSONOpt, false, fmt.Sprintf("Output logs in JSON format. Environment variable %s", COOLogJSON))
 	c.help = flags.Usage(help, c.flags)
 
 	return c, nil
@@ -139,6 +152,11 @@ func (c *Command) Run(args []string) int {
 		return -1
 	}
 
+	// now that the configuration is loaded replace the bootstrap logger with the configured one
+	logger = hclog.New(cfg.loggerOptions())
+	hclog.SetDefault(logger)
+	ctx = hclog.WithContext(ctx, logger)
+
 	cfg.logDeprecations(logger)
 
 	service, err := NewService(cfg)
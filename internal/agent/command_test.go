@@ -84,6 +84,8 @@ func Test_loadConfiguration(t *testing.T) {
 				HCPResourceID:      "rid",
 				COOtelHTTPEndpoint: "ep",
 				COOConfigPath:      "fp",
+				COOLogLevel:        "debug",
+				COOLogJSON:         "true",
 			},
 			mutateExpected: func(c *Config) {
 				c.Cloud.ClientID = "id"
@@ -91,6 +93,22 @@ func Test_loadConfiguration(t *testing.T) {
 				c.Cloud.ResourceID = "rid"
 				c.HTTPCollectorEndpoint = "ep"
 				c.ConfigFile = "fp"
+				c.LogLevel = "debug"
+				c.LogJSON = true
+			},
+		},
+		"SuccessWithLogFlags": {
+			args: []string{
+				wrapOpt(COOLogLevelOpt),
+				"trace",
+				wrapOpt(COOLogJSONOpt),
+			},
+			env: map[string]string{
+				COOLogLevel: "debug",
+			},
+			mutateExpected: func(c *Config) {
+				c.LogLevel = "trace"
+				c.LogJSON = true
 			},
 		},
 		"SuccessWithCliOptsPrecedenceOverEnvVariables": {
@@ -210,3 +228,16 @@ func Test_loadConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func Test_Help(t *testing.T) {
+	c, err := NewAgentCmd(&cli.BasicUi{})
+	test.NoError(t, err)
+
+	help := c.Help()
+	for _, endpoint := range []string{
+		"http://localhost:1777/debug/pprof/",
+		"http://localhost:55679/debug/pipelinez",
+	} {
+		test.StrContains(t, help, endpoint)
+	}
+}
@@ -32,4 +32,16 @@ const (
 
 	// COOConfigPathOpt is the cli opt for path to the config.
 	COOConfigPathOpt = "config-file-path"
+
+	// COOLogLevel is the environment variable for the log level.
+	COOLogLevel = "COO_LOG_LEVEL"
+
+	// COOLogLevelOpt is the cli opt for the log level.
+	COOLogLevelOpt = "log-level"
+
+	// COOLogJSON is the environment variable to output logs in JSON format.
+	COOLogJSON = "COO_LOG_JSON"
+
+	// COOLogJSONOpt is the cli opt to output logs in JSON format.
+	COOLogJSONOpt = "log-json"
 )
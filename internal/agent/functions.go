@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// evalContext returns the context the configuration file expressions are evaluated in. It provides env and file,
+// to keep secrets and per host values out of the file, and basic string functions. Relative paths passed to file
+// are resolved from dir, the directory of the configuration file.
+func evalContext(dir string) *hcl.EvalContext {
+	return &hcl.EvalContext{
+		Functions: map[string]function.Function{
+			"env":        envFunc,
+			"file":       fileFunc(dir),
+			"chomp":      stdlib.ChompFunc,
+			"format":     stdlib.FormatFunc,
+			"join":       stdlib.JoinFunc,
+			"lower":      stdlib.LowerFunc,
+			"replace":    stdlib.ReplaceFunc,
+			"split":      stdlib.SplitFunc,
+			"substr":     stdlib.SubstrFunc,
+			"trim":       stdlib.TrimFunc,
+			"trimprefix": stdlib.TrimPrefixFunc,
+			"trimspace":  stdlib.TrimSpaceFunc,
+			"trimsuffix": stdlib.TrimSuffixFunc,
+			"upper":      stdlib.UpperFunc,
+		},
+	}
+}
+
+// envFunc returns the value of an environment variable, or an empty string if it is not set.
+var envFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "name", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		return cty.StringVal(os.Getenv(args[0].AsString())), nil
+	},
+})
+
+// fileFunc returns the function reading the contents of a file. Relative paths are resolved from dir.
+func fileFunc(dir string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			path := args[0].AsString()
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				return cty.NilVal, function.NewArgError(0, err)
+			}
+			return cty.StringVal(string(contents)), nil
+		},
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_evalContext(t *testing.T) {
+	dir := t.TempDir()
+	must.NoError(t, os.WriteFile(filepath.Join(dir, "client_secret"), []byte("secret\n"), 0o600))
+	t.Setenv("TEST_HCP_CLIENT_ID", "id")
+	t.Setenv("TEST_HOSTNAME", "Node-1")
+
+	for name, tc := range map[string]struct {
+		config      string
+		json        bool
+		expect      *Config
+		errContains string
+	}{
+		"Env": {
+			config: `
+			cloud {
+				client_id = env("TEST_HCP_CLIENT_ID")
+			}
+			`,
+			expect: &Config{Cloud: &Cloud{ClientID: "id"}},
+		},
+		"EnvUnset": {
+			config: `
+			cloud {
+				client_id = env("TEST_UNSET")
+			}
+			`,
+			expect: &Config{Cloud: &Cloud{}},
+		},
+		"FileRelative": {
+			config: `
+			cloud {
+				client_secret = trimspace(file("client_secret"))
+			}
+			`,
+			expect: &Config{Cloud: &Cloud{ClientSecret: "secret"}},
+		},
+		"FileAbsolute": {
+			config: `
+			cloud {
+				client_secret = chomp(file("` + filepath.Join(dir, "client_secret") + `"))
+			}
+			`,
+			expect: &Config{Cloud: &Cloud{ClientSecret: "secret"}},
+		},
+		"StringFunctions": {
+			config: `
+			exporter_config "otlphttp" {
+				endpoint = format("https://%s.collector.internal:4318", lower(env("TEST_HOSTNAME")))
+				headers = {
+					authorization = join(" ", ["Bearer", upper("token")])
+				}
+			}
+			`,
+			expect: &Config{
+				ExporterConfig: &ExporterConfig{
+					Type:     "otlphttp",
+					Endpoint: "https://node-1.collector.internal:4318",
+					Headers:  map[string]string{"authorization": "Bearer TOKEN"},
+				},
+			},
+		},
+		"Interpolation": {
+			config: `
+			exporter_config "otlphttp" {
+				endpoint = "https://${env("TEST_HOSTNAME")}:4318"
+			}
+			`,
+			expect: &Config{
+				ExporterConfig: &ExporterConfig{
+					Type:     "otlphttp",
+					Endpoint: "https://Node-1:4318",
+				},
+			},
+		},
+		"JSON": {
+			json:   true,
+			config: `{"cloud":{"client_id":"${env(\"TEST_HCP_CLIENT_ID\")}"}}`,
+			expect: &Config{Cloud: &Cloud{ClientID: "id"}},
+		},
+		"FailFileMissing": {
+			config: `
+			cloud {
+				client_secret = file("missing")
+			}
+			`,
+			errContains: "config.hcl:3",
+		},
+		"FailUnknownFunction": {
+			config: `
+			cloud {
+				client_id = base64decode("aWQ=")
+			}
+			`,
+			errContains: "Call to unknown function",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			filename := "config.hcl"
+			if tc.json {
+				filename = "config.json"
+			}
+			cfg, err := readConfiguration(strings.NewReader(tc.config), filepath.Join(dir, filename))
+			if tc.errContains != "" {
+				must.ErrorContains(t, err, tc.errContains)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.expect, cfg)
+		})
+	}
+}
@@ -8,13 +8,24 @@ package agent
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 
+	"github.com/hashicorp/consul-telemetry-collector/extensions/healthcheckextension"
 	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/exporters"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/extensions"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/processors"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/types"
+	hcpprovider "github.com/hashicorp/consul-telemetry-collector/internal/otel/providers/hcp"
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver"
 	"github.com/hashicorp/go-hclog"
 )
 
@@ -26,7 +37,18 @@ type Service struct {
 
 // NewService returns a new Service based off the past in configuration.
 func NewService(cfg *Config) (*Service, error) {
-	s := &Service{}
+	proxyCfg := cfg.proxy()
+	if _, err := proxyCfg.CAPEM(); err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		cfg: otel.CollectorCfg{
+			LogLevel: cfg.LogLevel,
+			LogJSON:  cfg.LogJSON,
+			Proxy:    proxyCfg,
+		},
+	}
 
 	if cfg.HTTPCollectorEndpoint != "" {
 		s.cfg.ExporterConfig = &config.ExporterConfig{
@@ -38,28 +60,124 @@ func NewService(cfg *Config) (*Service, error) {
 	}
 
 	if cfg.Cloud != nil && cfg.Cloud.IsEnabled() {
+		workloadIdentity := cfg.Cloud.workloadIdentity()
 		hcpClient, err := hcp.New(&hcp.Params{
-			ClientID:     cfg.Cloud.ClientID,
-			ClientSecret: cfg.Cloud.ClientSecret,
-			ResourceURL:  cfg.Cloud.ResourceID,
+			ClientID:         cfg.Cloud.ClientID,
+			ClientSecret:     cfg.Cloud.ClientSecret,
+			ClientIDFile:     cfg.Cloud.ClientIDFile,
+			ClientSecretFile: cfg.Cloud.ClientSecretFile,
+			WorkloadIdentity: workloadIdentity,
+			ResourceURL:      cfg.Cloud.ResourceID,
+			Proxy:            proxyCfg,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to create hcp client %w", err)
 		}
 		s.cfg.ClientID = cfg.Cloud.ClientID
 		s.cfg.ClientSecret = cfg.Cloud.ClientSecret
+		s.cfg.ClientIDFile = cfg.Cloud.ClientIDFile
+		s.cfg.ClientSecretFile = cfg.Cloud.ClientSecretFile
+		s.cfg.WorkloadIdentity = workloadIdentity
 		s.cfg.Client = hcpClient
 		s.cfg.ResourceID = cfg.Cloud.ResourceID
 	}
 
 	if cfg.ExporterConfig != nil {
-		s.cfg.ExporterConfig = &config.ExporterConfig{
-			ID: component.NewID(component.Type(cfg.ExporterConfig.Type)),
-			Exporter: &exporters.ExporterConfig{
-				Headers:  cfg.ExporterConfig.Headers,
-				Endpoint: cfg.ExporterConfig.Endpoint,
-				Timeout:  cfg.ExporterConfig.Timeout,
-			},
+		s.cfg.ExporterConfig = cfg.ExporterConfig.config(component.NewID(component.Type(cfg.ExporterConfig.Type)))
+	}
+
+	if cfg.Health != nil {
+		s.cfg.HealthCheckEndpoint = cfg.Health.Endpoint
+		if s.cfg.HealthCheckEndpoint == "" {
+			s.cfg.HealthCheckEndpoint = healthcheckextension.DefaultEndpoint
+		}
+	}
+
+	if cfg.Debug != nil {
+		s.cfg.PprofEndpoint = cfg.Debug.PprofEndpoint
+		if s.cfg.PprofEndpoint == "" {
+			s.cfg.PprofEndpoint = extensions.DefaultPprofEndpoint
+		}
+		s.cfg.ZPagesEndpoint = cfg.Debug.ZPagesEndpoint
+		if s.cfg.ZPagesEndpoint == "" {
+			s.cfg.ZPagesEndpoint = extensions.DefaultZPagesEndpoint
+		}
+	}
+
+	if cfg.Telemetry != nil {
+		s.cfg.MetricsAddress = cfg.Telemetry.MetricsAddress
+		s.cfg.MetricsLevel = cfg.Telemetry.MetricsLevel
+		if cfg.Telemetry.ScrapeInterval != "" {
+			interval, err := time.ParseDuration(cfg.Telemetry.ScrapeInterval)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse scrape_interval %w", err)
+			}
+			s.cfg.ScrapeInterval = interval
+		}
+		if cfg.Telemetry.Exporter != nil {
+			s.cfg.SelfMetricsExporter = cfg.Telemetry.Exporter.config(
+				component.NewIDWithName(component.Type(cfg.Telemetry.Exporter.Type), "self"))
+		}
+	}
+
+	if cfg.CardinalityLimit != nil {
+		limit, err := cardinalityLimit(cfg.CardinalityLimit)
+		if err != nil {
+			return nil, err
+		}
+		s.cfg.CardinalityLimit = limit
+	}
+
+	if cfg.TagExtraction != nil {
+		s.cfg.TagExtraction = tagExtraction(cfg.TagExtraction)
+	}
+	s.cfg.StripUnitSuffixes = cfg.StripUnitSuffixes
+
+	if cfg.ResourceAttributes != nil {
+		s.cfg.ResourceAttributes = cfg.ResourceAttributes.config()
+	}
+
+	for _, r := range cfg.Routes {
+		s.cfg.Routes = append(s.cfg.Routes, route(r))
+	}
+
+	for _, c := range cfg.Clouds {
+		resource, err := hcpResource(c, &s.cfg)
+		if err != nil {
+			return nil, err
+		}
+		s.cfg.HCPResources = append(s.cfg.HCPResources, resource)
+	}
+
+	if cfg.Filter != nil {
+		filter := cfg.Filter.localFilter()
+		s.cfg.LocalFilter = &filter
+	}
+
+	if cfg.Rename != nil {
+		r := renames(cfg.Rename)
+		s.cfg.Renames = &r
+	}
+
+	for _, a := range cfg.Aggregations {
+		agg, err := aggregation(a)
+		if err != nil {
+			return nil, err
+		}
+		switch a.Pipeline {
+		case AggregationPipelineHCP:
+			s.cfg.HCPAggregation = agg
+		case AggregationPipelineExternal:
+			s.cfg.ExternalAggregation = agg
+		}
+	}
+
+	for _, r := range cfg.Redactions {
+		switch r.Pipeline {
+		case RedactionPipelineHCP:
+			s.cfg.HCPRedaction = r.config()
+		case RedactionPipelineExternal:
+			s.cfg.ExternalRedaction = r.config()
 		}
 	}
 
@@ -72,6 +190,216 @@ func NewService(cfg *Config) (*Service, error) {
 	return s, nil
 }
 
+// hcpResource creates the client of a named cloud block and the HCP resource its proxies are exported to. The node
+// metadata of the block is added to the resource attributes so the proxies can be matched by their values.
+func hcpResource(c *Cloud, cfg *otel.CollectorCfg) (hcpprovider.Resource, error) {
+	credentials := hcp.Credentials{
+		ClientID:         c.ClientID,
+		ClientSecret:     c.ClientSecret,
+		ClientIDFile:     c.ClientIDFile,
+		ClientSecretFile: c.ClientSecretFile,
+		WorkloadIdentity: c.workloadIdentity(),
+	}
+	client, err := hcp.New(&hcp.Params{
+		ClientID:         c.ClientID,
+		ClientSecret:     c.ClientSecret,
+		ClientIDFile:     c.ClientIDFile,
+		ClientSecretFile: c.ClientSecretFile,
+		WorkloadIdentity: credentials.WorkloadIdentity,
+		ResourceURL:      c.ResourceID,
+		Proxy:            cfg.Proxy,
+	})
+	if err != nil {
+		return hcpprovider.Resource{}, fmt.Errorf("failed to create hcp client of cloud %q %w", c.Name, err)
+	}
+
+	if cfg.ResourceAttributes == nil {
+		cfg.ResourceAttributes = &envoyreceiver.ResourceAttributesConfig{}
+	}
+	if cfg.ResourceAttributes.NodeMetadata == nil {
+		cfg.ResourceAttributes.NodeMetadata = make(map[string]string)
+	}
+	attributes := make(map[string]string, len(c.NodeMetadata))
+	for key, value := range c.NodeMetadata {
+		name, ok := cfg.ResourceAttributes.NodeMetadata[key]
+		if !ok {
+			cfg.ResourceAttributes.NodeMetadata[key] = ""
+		}
+		if name == "" {
+			name = key
+		}
+		attributes[name] = value
+	}
+
+	return hcpprovider.Resource{
+		Name:        c.Name,
+		ResourceID:  c.ResourceID,
+		Client:      client,
+		Credentials: credentials,
+		Attributes:  attributes,
+	}, nil
+}
+
+// cardinalityLimit converts the cardinality_limit block to the processor configuration. Limits that are not set
+// use the processor defaults.
+func cardinalityLimit(c *CardinalityLimit) (*cardinalityprocessor.Config, error) {
+	limit := cardinalityprocessor.CreateDefaultConfig().(*cardinalityprocessor.Config)
+	if c.MaxSeriesPerMetric != nil {
+		limit.MaxSeriesPerMetric = *c.MaxSeriesPerMetric
+	}
+	if c.MaxSeriesPerNode != nil {
+		limit.MaxSeriesPerNode = *c.MaxSeriesPerNode
+	}
+	if c.Window != "" {
+		window, err := time.ParseDuration(c.Window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cardinality limit window %w", err)
+		}
+		limit.Window = window
+	}
+	if c.OverflowAction != "" {
+		limit.OverflowAction = c.OverflowAction
+	}
+	return limit, nil
+}
+
+// tagExtraction converts the tag_extraction block to the envoy receiver configuration.
+func tagExtraction(t *TagExtraction) *envoyreceiver.TagExtractionConfig {
+	extraction := &envoyreceiver.TagExtractionConfig{
+		DisableDefaults: t.DisableDefaults,
+	}
+	for _, e := range t.Extractors {
+		extraction.Extractors = append(extraction.Extractors, envoyreceiver.TagExtractorConfig{
+			Name:  e.Name,
+			Regex: e.Regex,
+		})
+	}
+	return extraction
+}
+
+// route converts the route block to the route of the external pipeline. Its exporter is named after the route.
+// The redaction, when set, redacts the metrics of the route only.
+func route(r *Route) config.Route {
+	cfg := config.Route{
+		Name:      r.Name,
+		Partition: r.Partition,
+		Namespace: r.Namespace,
+		Exporter:  r.Exporter.config(component.NewIDWithName(component.Type(r.Exporter.Type), "route-"+r.Name)),
+	}
+	if r.Redaction != nil {
+		cfg.Redaction = r.Redaction.config()
+	}
+	return cfg
+}
+
+// config converts the exporter block to the exporter with the id. The tls block, when present, replaces the TLS
+// settings derived from the endpoint and the auth block adds the auth extension named after the exporter.
+func (e *ExporterConfig) config(id component.ID) *config.ExporterConfig {
+	cfg := &exporters.ExporterConfig{
+		Headers:  e.Headers,
+		Endpoint: e.Endpoint,
+		Timeout:  e.Timeout,
+	}
+	if e.TLS != nil {
+		cfg.TLSSetting = &types.TLSClientSetting{
+			CAFile:             e.TLS.CAFile,
+			CertFile:           e.TLS.CertFile,
+			KeyFile:            e.TLS.KeyFile,
+			ServerName:         e.TLS.ServerName,
+			MinVersion:         e.TLS.MinVersion,
+			Insecure:           e.TLS.Insecure,
+			InsecureSkipVerify: e.TLS.InsecureSkipVerify,
+		}
+	}
+	return &config.ExporterConfig{
+		ID:            id,
+		Exporter:      cfg,
+		Authenticator: e.Auth.authenticator(strings.ReplaceAll(id.String(), "/", "-")),
+	}
+}
+
+// authenticator converts the auth block to the auth extension of the named exporter. It returns nil when there is
+// no auth block.
+func (a *ExporterAuth) authenticator(name string) *config.Authenticator {
+	switch {
+	case a == nil:
+		return nil
+	case a.BearerToken != nil:
+		return &config.Authenticator{
+			ID:     extensions.BearerTokenAuthIDFor(name),
+			Config: extensions.BearerTokenAuthCfg(a.BearerToken.Scheme, a.BearerToken.TokenFile),
+		}
+	case a.Basic != nil:
+		return &config.Authenticator{
+			ID:     extensions.BasicAuthIDFor(name),
+			Config: extensions.BasicAuthCfg(a.Basic.Username, a.Basic.Password),
+		}
+	case a.OAuth2 != nil:
+		params := make(url.Values, len(a.OAuth2.EndpointParams))
+		for k, v := range a.OAuth2.EndpointParams {
+			params.Set(k, v)
+		}
+		return &config.Authenticator{
+			ID: extensions.OauthClientExporterIDFor(name),
+			Config: extensions.OauthClientCredentialsCfg(a.OAuth2.ClientID, a.OAuth2.ClientSecret,
+				a.OAuth2.TokenURL, a.OAuth2.Scopes, params),
+		}
+	default:
+		return nil
+	}
+}
+
+// renames converts the rename block to the metric and attribute renames, applying its overrides to the defaults.
+func renames(r *Rename) processors.Renames {
+	renames := processors.Renames{
+		Metrics:    map[string]string{},
+		Attributes: map[string]string{},
+	}
+	if !r.DisableDefaults {
+		renames = processors.DefaultRenames()
+	}
+
+	for _, overrides := range []struct {
+		from, into map[string]string
+	}{
+		{r.Metrics, renames.Metrics},
+		{r.Attributes, renames.Attributes},
+	} {
+		for name, newName := range overrides.from {
+			if newName == "" {
+				delete(overrides.into, name)
+				continue
+			}
+			overrides.into[name] = newName
+		}
+	}
+	return renames
+}
+
+// aggregation converts an aggregation block to the processor configuration. Unset values use the processor
+// defaults.
+func aggregation(a *Aggregation) (*aggregationprocessor.Config, error) {
+	agg := &aggregationprocessor.Config{
+		DropResourceAttributes: a.DropResourceAttributes,
+		GaugeFunction:          a.GaugeFunction,
+	}
+	if a.Interval != "" {
+		interval, err := time.ParseDuration(a.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse aggregation interval %w", err)
+		}
+		agg.Interval = interval
+	}
+	if a.MaxStaleness != "" {
+		staleness, err := time.ParseDuration(a.MaxStaleness)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse aggregation max_staleness %w", err)
+		}
+		agg.MaxStaleness = staleness
+	}
+	return agg, nil
+}
+
 // Run will initialize and Start the consul-telemetry-collector Service.
 func (s *Service) Run(ctx context.Context) error {
 	logger := hclog.FromContext(ctx)
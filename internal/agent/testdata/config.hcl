@@ -0,0 +1,60 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+log_level = "debug"
+log_json  = true
+
+cloud {
+  client_id     = env("TEST_HCP_CLIENT_ID")
+  client_secret = "secret"
+  resource_id   = "organization/00000000-0000-0000-0000-000000000000/project/00000000-0000-0000-0000-000000000001/hashicorp.consul.cluster/test"
+}
+
+cloud {
+  name          = "west"
+  client_id     = "west-id"
+  client_secret = "west-secret"
+  resource_id   = "organization/00000000-0000-0000-0000-000000000000/project/00000000-0000-0000-0000-000000000001/hashicorp.consul.cluster/west"
+  node_metadata = {
+    region = "us-west-2"
+  }
+}
+
+exporter_config "otlphttp" {
+  endpoint = "https://collector.internal:4318"
+  headers = {
+    x-tenant = "platform"
+  }
+
+  tls {
+    ca_file     = "/etc/ssl/ca.pem"
+    min_version = "1.3"
+  }
+
+  auth {
+    bearer_token {
+      token_file = "/var/run/secrets/token"
+    }
+  }
+}
+
+route "team-a" {
+  partition = "team-a"
+
+  exporter "otlp" {
+    endpoint = "team-a.collector.internal:4317"
+    timeout  = "10s"
+  }
+}
+
+cardinality_limit {
+  max_series_per_metric = 1000
+}
+
+filter {
+  exclude = ["envoy_cluster_.*"]
+}
+
+health {}
+
+strip_unit_suffixes = true
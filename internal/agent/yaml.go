@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"gopkg.in/yaml.v3"
+)
+
+// decodeYAML decodes a YAML configuration file into the target, like hclsimple.Decode does for HCL and JSON files.
+// The YAML is converted to the HCL JSON syntax so both share the schema of the target, including its expressions.
+// The JSON keeps every value on the line of the YAML source so that the errors point to it.
+func decodeYAML(filename string, src []byte, ctx *hcl.EvalContext, target any) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(src, &doc); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	w := &yamlJSONWriter{line: 1, column: 1}
+	if len(doc.Content) == 0 {
+		w.buf.WriteString("{}")
+	} else if err := w.write(doc.Content[0]); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	file, diags := hcljson.Parse(w.buf.Bytes(), filename)
+	if diags.HasErrors() {
+		return diags
+	}
+	if diags := gohcl.DecodeBody(file.Body, ctx, target); diags.HasErrors() {
+		return diags
+	}
+	return nil
+}
+
+// yamlJSONWriter writes YAML nodes as JSON, tracking the line and column written so far to move each node to the
+// position of its YAML source.
+type yamlJSONWriter struct {
+	buf    bytes.Buffer
+	line   int
+	column int
+}
+
+// write writes the node and its children as JSON.
+func (w *yamlJSONWriter) write(n *yaml.Node) error {
+	w.moveTo(n)
+	switch n.Kind {
+	case yaml.MappingNode:
+		w.writeString("{")
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: mapping keys must be scalars", key.Line)
+			}
+			if i > 0 {
+				w.writeString(",")
+			}
+			w.moveTo(key)
+			w.writeJSON(key.Value)
+			w.writeString(":")
+			if err := w.write(value); err != nil {
+				return err
+			}
+		}
+		w.writeString("}")
+	case yaml.SequenceNode:
+		w.writeString("[")
+		for i, item := range n.Content {
+			if i > 0 {
+				w.writeString(",")
+			}
+			if err := w.write(item); err != nil {
+				return err
+			}
+		}
+		w.writeString("]")
+	case yaml.AliasNode:
+		return w.write(n.Alias)
+	case yaml.ScalarNode:
+		return w.writeScalar(n)
+	default:
+		return fmt.Errorf("line %d: unsupported YAML node", n.Line)
+	}
+	return nil
+}
+
+// writeScalar writes a scalar as a JSON string, number, boolean or null according to its YAML tag.
+func (w *yamlJSONWriter) writeScalar(n *yaml.Node) error {
+	if n.ShortTag() == "!!str" {
+		w.writeJSON(n.Value)
+		return nil
+	}
+
+	var v any
+	if err := n.Decode(&v); err != nil {
+		return fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("line %d: %q cannot be converted: %w", n.Line, n.Value, err)
+	}
+	w.writeString(string(b))
+	return nil
+}
+
+// moveTo writes the newlines and spaces moving to the position of the node. Nodes written after a later one, like
+// the target of an alias, stay where they are.
+func (w *yamlJSONWriter) moveTo(n *yaml.Node) {
+	for w.line < n.Line {
+		w.writeString("\n")
+	}
+	if w.line == n.Line && w.column < n.Column {
+		w.writeString(strings.Repeat(" ", n.Column-w.column))
+	}
+}
+
+// writeJSON writes the value encoded as JSON. Strings never contain raw newlines once encoded.
+func (w *yamlJSONWriter) writeJSON(v any) {
+	b, _ := json.Marshal(v)
+	w.writeString(string(b))
+}
+
+func (w *yamlJSONWriter) writeString(s string) {
+	w.buf.WriteString(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		w.line += strings.Count(s, "\n")
+		w.column = len(s) - i
+		return
+	}
+	w.column += len(s)
+}
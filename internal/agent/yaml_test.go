@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package agent
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_parseFileFormats(t *testing.T) {
+	t.Setenv("TEST_HCP_CLIENT_ID", "id")
+
+	expect := &Config{
+		LogLevel: "debug",
+		LogJSON:  true,
+		Cloud: &Cloud{
+			ClientID:     "id",
+			ClientSecret: "secret",
+			ResourceID: "organization/00000000-0000-0000-0000-000000000000/project/" +
+				"00000000-0000-0000-0000-000000000001/hashicorp.consul.cluster/test",
+		},
+		Clouds: []*Cloud{
+			{
+				Name:         "west",
+				ClientID:     "west-id",
+				ClientSecret: "west-secret",
+				ResourceID: "organization/00000000-0000-0000-0000-000000000000/project/" +
+					"00000000-0000-0000-0000-000000000001/hashicorp.consul.cluster/west",
+				NodeMetadata: map[string]string{"region": "us-west-2"},
+			},
+		},
+		ExporterConfig: &ExporterConfig{
+			Type:     "otlphttp",
+			Endpoint: "https://collector.internal:4318",
+			Headers:  map[string]string{"x-tenant": "platform"},
+			TLS: &ExporterTLS{
+				CAFile:     "/etc/ssl/ca.pem",
+				MinVersion: "1.3",
+			},
+			Auth: &ExporterAuth{
+				BearerToken: &BearerTokenAuth{TokenFile: "/var/run/secrets/token"},
+			},
+		},
+		Routes: []*Route{
+			{
+				Name:      "team-a",
+				Partition: "team-a",
+				Exporter: &ExporterConfig{
+					Type:     "otlp",
+					Endpoint: "team-a.collector.internal:4317",
+					Timeout:  "10s",
+				},
+			},
+		},
+		CardinalityLimit:  &CardinalityLimit{MaxSeriesPerMetric: ptr(1000)},
+		Filter:            &Filter{Exclude: []string{"envoy_cluster_.*"}},
+		Health:            &Health{},
+		StripUnitSuffixes: true,
+	}
+
+	for _, filename := range []string{"config.hcl", "config.json", "config.yaml"} {
+		t.Run(filename, func(t *testing.T) {
+			cfg, err := parseFile(filepath.Join("testdata", filename))
+			must.NoError(t, err)
+			must.Eq(t, expect, cfg)
+			must.NoError(t, cfg.validate())
+		})
+	}
+}
+
+func Test_decodeYAML(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config      string
+		filename    string
+		expect      *Config
+		errContains string
+	}{
+		"Empty": {
+			config: ``,
+			expect: &Config{},
+		},
+		"YML": {
+			filename: "config.yml",
+			config: `
+cloud:
+  client_id: id
+  client_secret: secret
+`,
+			expect: &Config{Cloud: &Cloud{ClientID: "id", ClientSecret: "secret"}},
+		},
+		"Anchors": {
+			config: `
+exporter_config:
+  otlphttp:
+    endpoint: https://collector.internal:4318
+    headers: &headers
+      x-tenant: platform
+telemetry:
+  exporter:
+    otlp:
+      endpoint: collector.internal:4317
+      headers: *headers
+`,
+			expect: &Config{
+				ExporterConfig: &ExporterConfig{
+					Type:     "otlphttp",
+					Endpoint: "https://collector.internal:4318",
+					Headers:  map[string]string{"x-tenant": "platform"},
+				},
+				Telemetry: &Telemetry{
+					Exporter: &ExporterConfig{
+						Type:     "otlp",
+						Endpoint: "collector.internal:4317",
+						Headers:  map[string]string{"x-tenant": "platform"},
+					},
+				},
+			},
+		},
+		"FailSyntax": {
+			config: `
+cloud:
+  client_id: id
+  client_secret: secret: other
+`,
+			errContains: "config.yaml: yaml: line 4",
+		},
+		"FailUnsupportedArgument": {
+			config: `
+cloud:
+  client_id: id
+
+  client_secrets: secret
+`,
+			errContains: "config.yaml:5,3-19: Extraneous JSON object property",
+		},
+		"FailInvalidType": {
+			config: `
+log_level: debug
+log_json:
+  - true
+`,
+			errContains: "config.yaml:4",
+		},
+		"FailFunction": {
+			config: `
+cloud:
+  client_id: id
+  client_secret: ${file("missing")}
+`,
+			errContains: "config.yaml:4",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			filename := tc.filename
+			if filename == "" {
+				filename = "config.yaml"
+			}
+			cfg, err := readConfiguration(strings.NewReader(tc.config), filename)
+			if tc.errContains != "" {
+				must.ErrorContains(t, err, tc.errContains)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.expect, cfg)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package datapoint merges OTLP data points of the same metric. It is shared by the processors that fold series
+// together, like the cardinality limiter and the aggregation processor.
+package datapoint
+
+import (
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// MergeSum adds the value of from to into.
+func MergeSum(into, from pmetric.NumberDataPoint) {
+	if into.ValueType() == pmetric.NumberDataPointValueTypeInt && from.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		into.SetIntValue(into.IntValue() + from.IntValue())
+	} else {
+		into.SetDoubleValue(numberValue(into) + numberValue(from))
+	}
+	mergeTimestamps(into, from)
+}
+
+// MergeGauge keeps the most recent value.
+func MergeGauge(into, from pmetric.NumberDataPoint) {
+	if from.Timestamp() >= into.Timestamp() {
+		switch from.ValueType() {
+		case pmetric.NumberDataPointValueTypeInt:
+			into.SetIntValue(from.IntValue())
+		case pmetric.NumberDataPointValueTypeDouble, pmetric.NumberDataPointValueTypeEmpty:
+			into.SetDoubleValue(from.DoubleValue())
+		}
+	}
+	mergeTimestamps(into, from)
+}
+
+// MergeMax keeps the largest value.
+func MergeMax(into, from pmetric.NumberDataPoint) {
+	if numberValue(from) > numberValue(into) {
+		switch from.ValueType() {
+		case pmetric.NumberDataPointValueTypeInt:
+			into.SetIntValue(from.IntValue())
+		case pmetric.NumberDataPointValueTypeDouble, pmetric.NumberDataPointValueTypeEmpty:
+			into.SetDoubleValue(from.DoubleValue())
+		}
+	}
+	mergeTimestamps(into, from)
+}
+
+// MergeHistogram adds the counts of from to into. The bucket counts are only merged when both data points
+// have the same bounds, otherwise the buckets are removed.
+func MergeHistogram(into, from pmetric.HistogramDataPoint) {
+	into.SetCount(into.Count() + from.Count())
+	if into.HasSum() || from.HasSum() {
+		into.SetSum(into.Sum() + from.Sum())
+	}
+	if from.HasMin() && (!into.HasMin() || from.Min() < into.Min()) {
+		into.SetMin(from.Min())
+	}
+	if from.HasMax() && (!into.HasMax() || from.Max() > into.Max()) {
+		into.SetMax(from.Max())
+	}
+
+	if slices.Equal(into.ExplicitBounds().AsRaw(), from.ExplicitBounds().AsRaw()) && into.BucketCounts().Len() == from.BucketCounts().Len() {
+		for i := 0; i < into.BucketCounts().Len(); i++ {
+			into.BucketCounts().SetAt(i, into.BucketCounts().At(i)+from.BucketCounts().At(i))
+		}
+	} else {
+		into.ExplicitBounds().FromRaw(nil)
+		into.BucketCounts().FromRaw(nil)
+	}
+
+	if from.StartTimestamp() < into.StartTimestamp() {
+		into.SetStartTimestamp(from.StartTimestamp())
+	}
+	if from.Timestamp() > into.Timestamp() {
+		into.SetTimestamp(from.Timestamp())
+	}
+}
+
+func mergeTimestamps(into, from pmetric.NumberDataPoint) {
+	if from.StartTimestamp() < into.StartTimestamp() {
+		into.SetStartTimestamp(from.StartTimestamp())
+	}
+	if from.Timestamp() > into.Timestamp() {
+		into.SetTimestamp(from.Timestamp())
+	}
+}
+
+func numberValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+// Count returns the number of data points of the metric.
+func Count(m pmetric.Metric) int {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().Len()
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return m.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return m.Summary().DataPoints().Len()
+	case pmetric.MetricTypeEmpty:
+	}
+	return 0
+}
@@ -4,38 +4,77 @@
 package hcp
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/go-openapi/runtime"
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/hashicorp/go-cleanhttp"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
 	"github.com/hashicorp/hcp-sdk-go/clients/cloud-consul-telemetry-gateway/preview/2023-04-14/client/consul_telemetry_service"
 	hcpconfig "github.com/hashicorp/hcp-sdk-go/config"
-	"github.com/hashicorp/hcp-sdk-go/httpclient"
 	"github.com/hashicorp/hcp-sdk-go/profile"
 	"github.com/hashicorp/hcp-sdk-go/resource"
+	"github.com/hashicorp/hcp-sdk-go/version"
 )
 
 // Params is structure used to hold parameters to generate a new client.
 type Params struct {
 	ClientID, ClientSecret, ResourceURL string
+	// ClientIDFile and ClientSecretFile, when set, are read for the credentials instead of the ClientID and
+	// ClientSecret. They are read again before every request so rotated credentials are used.
+	ClientIDFile, ClientSecretFile string
+	// WorkloadIdentity, when enabled, authenticates by exchanging a workload identity token instead of with the
+	// client credentials.
+	WorkloadIdentity WorkloadIdentity
+	// Proxy, when enabled, is the proxy the requests to the HCP API and auth endpoints go through.
+	Proxy *proxy.Config
 }
 
-// telemetryConfig is an internal structure use to store values from the ccm result
-// from the api. We use a temporary structure to be able to vary responses between
-// versions of the api so we don't have to handle multiple payload versions.
-type telemetryConfig struct {
-	labels      map[string]string
-	endpoint    string
-	includeList []string
+func (p *Params) credentials() Credentials {
+	return Credentials{
+		ClientID:         p.ClientID,
+		ClientSecret:     p.ClientSecret,
+		ClientIDFile:     p.ClientIDFile,
+		ClientSecretFile: p.ClientSecretFile,
+		WorkloadIdentity: p.WorkloadIdentity,
+	}
 }
 
+// defaultRequestTimeout bounds the requests to HCP.
+const defaultRequestTimeout = 30 * time.Second
+
 // Client provides a TelemetryClient that lazily retrieves configuration from HCP.
-// TelemtryConfiguration can be loaded on-demand using the ReloadConfig() function.
+// TelemtryConfiguration can be loaded on-demand using the ReloadConfig() function. It is safe for concurrent use.
 type Client struct {
-	metricCfg     *telemetryConfig
-	hcpResource   *resource.Resource
+	hcpResource *resource.Resource
+	credentials Credentials
+	// newClientService builds a client service authenticating with the credentials. It is nil when the client
+	// service can't be rebuilt, in which case the credentials are not rotated.
+	newClientService func(clientID, clientSecret string) (agentTelemetryConfigClient, error)
+	// requestTimeout bounds each request to HCP.
+	requestTimeout time.Duration
+
+	// reloadMu serializes the reloads, so concurrent callers retrieve the configuration once, and guards the
+	// clientService and its credentials.
+	reloadMu      sync.Mutex
 	clientService agentTelemetryConfigClient
+	// clientID and clientSecret are the credentials the clientService authenticates with.
+	clientID, clientSecret string
+
+	// mu guards the metricCfg.
+	mu        sync.RWMutex
+	metricCfg *TelemetryConfig
 }
 
 var _ TelemetryClient = (*Client)(nil)
@@ -52,7 +91,10 @@ type agentTelemetryConfigClient interface {
 	) (*consul_telemetry_service.AgentTelemetryConfigOK, error)
 }
 
-const sourceChannel = "consul-telemetry"
+const (
+	sourceChannel       = "consul-telemetry"
+	sourceChannelHeader = "X-HCP-Source-Channel"
+)
 
 // New creates a new telemetry client for the provided resource using the credentials.
 func New(p *Params) (*Client, error) {
@@ -60,20 +102,86 @@ func New(p *Params) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	if p.WorkloadIdentity.IsEnabled() {
+		// the workload identity token is read on every exchange so there are no credentials to rotate.
+		clientService, err := newTelemetryServiceClient(p, r)
+		if err != nil {
+			return nil, err
+		}
+		return newClient(p, clientService)
+	}
+
+	credentials := p.credentials()
+	clientID, clientSecret, err := credentials.Load()
+	if err != nil {
+		return nil, err
+	}
+	newClientService := func(clientID, clientSecret string) (agentTelemetryConfigClient, error) {
+		return newTelemetryServiceClient(&Params{ClientID: clientID, ClientSecret: clientSecret, Proxy: p.Proxy}, r)
+	}
+	clientService, err := newClientService(clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newClient(p, clientService)
+	if err != nil {
+		return nil, err
+	}
+	client.clientID = clientID
+	client.clientSecret = clientSecret
+	client.newClientService = newClientService
+	return client, nil
+}
+
+// newTelemetryServiceClient creates the HCP API client authenticating with the credentials or the workload identity
+// of the params.
+func newTelemetryServiceClient(p *Params, r *resource.Resource) (agentTelemetryConfigClient, error) {
 	hcpConfig, err := parseConfig(p, r)
 	if err != nil {
 		return nil, err
 	}
-	runtime, err := httpclient.New(httpclient.Config{
-		HCPConfig:     hcpConfig,
-		SourceChannel: sourceChannel,
-	})
+	runtime, err := newRuntime(hcpConfig, p.Proxy)
 	if err != nil {
 		return nil, err
 	}
 
-	client := consul_telemetry_service.New(runtime, nil)
-	return newClient(p, client)
+	return consul_telemetry_service.New(runtime, nil), nil
+}
+
+// newRuntime returns the runtime of the HCP API client, like the SDK's httpclient.New does, with its connections
+// sent through the proxy.
+func newRuntime(cfg hcpconfig.HCPConfig, p *proxy.Config) (*httptransport.Runtime, error) {
+	scheme := "https"
+	if cfg.APITLSConfig() == nil {
+		scheme = "http"
+	}
+
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSClientConfig = cfg.APITLSConfig()
+	if err := p.Apply(transport); err != nil {
+		return nil, err
+	}
+
+	runtime := httptransport.New(cfg.APIAddress(), "", []string{scheme})
+	runtime.Transport = &sourceChannelTransport{
+		sourceChannel: fmt.Sprintf("%s hcp-go-sdk/%s", sourceChannel, version.Version),
+		base:          &oauth2.Transport{Base: transport, Source: cfg},
+	}
+	return runtime, nil
+}
+
+// sourceChannelTransport sets the source channel header of the requests to the HCP API.
+type sourceChannelTransport struct {
+	sourceChannel string
+	base          http.RoundTripper
+}
+
+// RoundTrip sets the source channel header of a clone of the request, and sends it with the base transport.
+func (t *sourceChannelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(sourceChannelHeader, t.sourceChannel)
+	return t.base.RoundTrip(req)
 }
 
 // newClient is an internal implementation that takes a clientFn to do deped.
@@ -84,8 +192,10 @@ func newClient(p *Params, gnmClient agentTelemetryConfigClient) (*Client, error)
 	}
 
 	return &Client{
-		hcpResource:   r,
-		clientService: gnmClient,
+		hcpResource:    r,
+		clientService:  gnmClient,
+		credentials:    p.credentials(),
+		requestTimeout: defaultRequestTimeout,
 	}, nil
 }
 
@@ -99,13 +209,22 @@ func parseResource(res string) (*resource.Resource, error) {
 }
 
 func parseConfig(p *Params, r *resource.Resource) (hcpconfig.HCPConfig, error) {
-	if p.ClientID == "" || p.ClientSecret == "" {
+	clientID, clientSecret := p.ClientID, p.ClientSecret
+	if p.WorkloadIdentity.IsEnabled() {
+		if p.WorkloadIdentity.TokenFile == "" {
+			return nil, errors.New("workload identity token file is empty")
+		}
+		// the SDK only builds a configuration with client credentials. Its token source is replaced by the
+		// workload identity one so they are never sent.
+		clientID, clientSecret = workloadIdentityClientID, workloadIdentityClientID
+	}
+	if clientID == "" || clientSecret == "" {
 		return nil, errors.New("client credentials are empty")
 	}
 
-	hcpconfig, err := hcpconfig.NewHCPConfig(
+	cfg, err := hcpconfig.NewHCPConfig(
 		hcpconfig.FromEnv(),
-		hcpconfig.WithClientCredentials(p.ClientID, p.ClientSecret),
+		hcpconfig.WithClientCredentials(clientID, clientSecret),
 		hcpconfig.WithProfile(&profile.UserProfile{
 			OrganizationID: r.Organization,
 			ProjectID:      r.Project,
@@ -114,12 +233,147 @@ func parseConfig(p *Params, r *resource.Resource) (hcpconfig.HCPConfig, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to build hcp config: %w", err)
 	}
-	return hcpconfig, nil
+
+	if p.WorkloadIdentity.IsEnabled() {
+		return newWorkloadIdentityConfig(cfg, p.WorkloadIdentity, p.Proxy)
+	}
+	return newClientCredentialsConfig(cfg, clientID, clientSecret, p.Proxy)
+}
+
+// workloadIdentityClientID is the placeholder client id and secret the SDK configuration of a workload identity is
+// built with.
+const workloadIdentityClientID = "workload-identity"
+
+const (
+	defaultAuthURL  = "https://auth.idp.hashicorp.com"
+	authTokenPath   = "/oauth2/token"
+	authAudienceKey = "audience"
+	authAudience    = "https://api.hashicorp.cloud"
+
+	// envVarAuthURL and envVarAuthTLS are the environment variables the SDK reads the auth endpoint from.
+	envVarAuthURL      = "HCP_AUTH_URL"
+	envVarAuthTLS      = "HCP_AUTH_TLS"
+	tlsSettingInsecure = "insecure"
+)
+
+// tokenConfig is an HCP configuration whose access tokens come from its own token source, instead of the SDK's one
+// which connects with a transport of its own.
+type tokenConfig struct {
+	hcpconfig.HCPConfig
+	tokenSource oauth2.TokenSource
+}
+
+// newClientCredentialsConfig returns the configuration retrieving the access tokens of the client credentials from
+// the auth endpoint the SDK is configured with, through the proxy.
+func newClientCredentialsConfig(cfg hcpconfig.HCPConfig, clientID, clientSecret string, p *proxy.Config,
+) (*tokenConfig, error) {
+	authURL, ok := os.LookupEnv(envVarAuthURL)
+	if !ok {
+		authURL = defaultAuthURL
+	}
+	tokenURL, err := url.JoinPath(authURL, authTokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", envVarAuthURL, err)
+	}
+
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if os.Getenv(envVarAuthTLS) == tlsSettingInsecure {
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+	if err := p.Apply(transport); err != nil {
+		return nil, err
+	}
+
+	credentials := clientcredentials.Config{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		TokenURL:       tokenURL,
+		EndpointParams: url.Values{authAudienceKey: []string{authAudience}},
+	}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient,
+		&http.Client{Transport: transport, Timeout: defaultRequestTimeout})
+	return &tokenConfig{
+		HCPConfig:   cfg,
+		tokenSource: credentials.TokenSource(ctx),
+	}, nil
+}
+
+// newWorkloadIdentityConfig returns the configuration exchanging the workload identity token with the HCP API of
+// the cfg, through the proxy.
+func newWorkloadIdentityConfig(cfg hcpconfig.HCPConfig, w WorkloadIdentity, p *proxy.Config) (*tokenConfig, error) {
+	scheme := "https"
+	if cfg.APITLSConfig() == nil {
+		scheme = "http"
+	}
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSClientConfig = cfg.APITLSConfig()
+	if err := p.Apply(transport); err != nil {
+		return nil, err
+	}
+
+	apiURL := fmt.Sprintf("%s://%s", scheme, cfg.APIAddress())
+	return &tokenConfig{
+		HCPConfig:   cfg,
+		tokenSource: w.TokenSource(apiURL, &http.Client{Transport: transport, Timeout: defaultRequestTimeout}),
+	}, nil
+}
+
+// Token returns an access token of the token source.
+func (c *tokenConfig) Token() (*oauth2.Token, error) {
+	return c.tokenSource.Token()
+}
+
+// ReloadConfig will retrieve the telemetry configuration from HCP using the initially configured runtime. The
+// request is canceled with the ctx, or after the request timeout.
+func (c *Client) ReloadConfig(ctx context.Context) error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+	return c.reload(ctx)
+}
+
+// TelemetryConfig returns a snapshot of the telemetry configuration, retrieving it from HCP when it has not been
+// yet.
+func (c *Client) TelemetryConfig(ctx context.Context) (TelemetryConfig, error) {
+	if cfg, ok := c.snapshot(); ok {
+		return cfg, nil
+	}
+
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+	// the configuration may have been retrieved while waiting for another caller's reload.
+	if cfg, ok := c.snapshot(); ok {
+		return cfg, nil
+	}
+	if err := c.reload(ctx); err != nil {
+		return TelemetryConfig{}, err
+	}
+	cfg, _ := c.snapshot()
+	return cfg, nil
+}
+
+// snapshot returns a copy of the last retrieved telemetry configuration, or false if none was retrieved.
+func (c *Client) snapshot() (TelemetryConfig, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.metricCfg == nil {
+		return TelemetryConfig{}, false
+	}
+	return c.metricCfg.clone(), true
 }
 
-// ReloadConfig will retrieve the telemetry configuration from HCP using the initially configured runtime.
-func (c *Client) ReloadConfig() error {
-	params := consul_telemetry_service.NewAgentTelemetryConfigParams()
+// reload retrieves the telemetry configuration. The reloadMu must be held.
+func (c *Client) reload(ctx context.Context) error {
+	if err := c.rotateCredentials(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	params := consul_telemetry_service.NewAgentTelemetryConfigParamsWithContext(ctx)
+	params.SetLocationOrganizationID(c.hcpResource.Organization)
+	params.SetLocationProjectID(c.hcpResource.Project)
 	params.SetClusterID(c.hcpResource.ID)
 	result, err := c.clientService.AgentTelemetryConfig(params, nil)
 	if err != nil {
@@ -129,42 +383,39 @@ func (c *Client) ReloadConfig() error {
 	if result.Payload.TelemetryConfig.Metrics.Endpoint != "" {
 		endpoint = result.Payload.TelemetryConfig.Metrics.Endpoint
 	}
-	metricCfg := telemetryConfig{
-		labels:      result.Payload.TelemetryConfig.Labels,
-		endpoint:    endpoint,
-		includeList: result.Payload.TelemetryConfig.Metrics.IncludeList,
+	metricCfg := TelemetryConfig{
+		Labels:      result.Payload.TelemetryConfig.Labels,
+		Endpoint:    endpoint,
+		IncludeList: result.Payload.TelemetryConfig.Metrics.IncludeList,
 	}
 
+	c.mu.Lock()
 	c.metricCfg = &metricCfg
+	c.mu.Unlock()
 	return nil
 }
 
-// MetricsEndpoint returns the metrics endpoint from the TelemetryConfig.
-func (c *Client) MetricsEndpoint() (string, error) {
-	if c.metricCfg == nil {
-		if err := c.ReloadConfig(); err != nil {
-			return "", err
-		}
+// rotateCredentials rebuilds the client service when the credentials changed since it was built. The reloadMu
+// must be held.
+func (c *Client) rotateCredentials() error {
+	if c.newClientService == nil {
+		return nil
 	}
-	return c.metricCfg.endpoint, nil
-}
 
-// MetricFilters returns the metric inclusion filters from the TelemetryConfig.
-func (c *Client) MetricFilters() ([]string, error) {
-	if c.metricCfg == nil {
-		if err := c.ReloadConfig(); err != nil {
-			return nil, err
-		}
+	clientID, clientSecret, err := c.credentials.Load()
+	if err != nil {
+		return err
+	}
+	if clientID == c.clientID && clientSecret == c.clientSecret {
+		return nil
 	}
-	return c.metricCfg.includeList, nil
-}
 
-// MetricAttributes returns the labels we were told to include from the TelemetryConfig.
-func (c *Client) MetricAttributes() (map[string]string, error) {
-	if c.metricCfg == nil {
-		if err := c.ReloadConfig(); err != nil {
-			return nil, err
-		}
+	clientService, err := c.newClientService(clientID, clientSecret)
+	if err != nil {
+		return fmt.Errorf("failed to rotate the client credentials: %w", err)
 	}
-	return c.metricCfg.labels, nil
+	c.clientService = clientService
+	c.clientID = clientID
+	c.clientSecret = clientSecret
+	return nil
 }
@@ -4,13 +4,22 @@
 package hcp
 
 import (
+	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	oErrors "github.com/go-openapi/errors"
+	"github.com/go-openapi/runtime"
 	"github.com/google/uuid"
+	"github.com/shoenig/test"
 	"github.com/shoenig/test/must"
 
+	"github.com/hashicorp/consul-telemetry-collector/internal/hcp/fakehcp"
 	"github.com/hashicorp/hcp-sdk-go/clients/cloud-consul-telemetry-gateway/preview/2023-04-14/client/consul_telemetry_service"
 	"github.com/hashicorp/hcp-sdk-go/clients/cloud-consul-telemetry-gateway/preview/2023-04-14/models"
 	"github.com/hashicorp/hcp-sdk-go/resource"
@@ -54,13 +63,13 @@ func Test_ParseConfig(t *testing.T) {
 		p             *Params
 		expectedError error
 	}{
-		"success": {p: &Params{uuid.NewString(), uuid.NewString(), testResource().String()}},
+		"success": {p: &Params{ClientID: uuid.NewString(), ClientSecret: uuid.NewString(), ResourceURL: testResource().String()}},
 		"emptyclientid": {
-			p:             &Params{"", uuid.NewString(), testResource().String()},
+			p:             &Params{ClientSecret: uuid.NewString(), ResourceURL: testResource().String()},
 			expectedError: errors.New("client credentials are empty"),
 		},
 		"emptyclientsec": {
-			p:             &Params{uuid.NewString(), "", testResource().String()},
+			p:             &Params{ClientID: uuid.NewString(), ResourceURL: testResource().String()},
 			expectedError: errors.New("client credentials are empty"),
 		},
 	} {
@@ -118,7 +127,7 @@ func Test_New(t *testing.T) {
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
-			_, err := New(&Params{tc.cid, tc.csec, tc.res.String()})
+			_, err := New(&Params{ClientID: tc.cid, ClientSecret: tc.csec, ResourceURL: tc.res.String()})
 			if tc.wantErr {
 				must.Error(t, err)
 				return
@@ -182,13 +191,13 @@ func TestLoadConfig(t *testing.T) {
 				MockResponse: tc.resp,
 				Err:          tc.err,
 			}
-			p := &Params{uuid.NewString(), uuid.NewString(), tc.r.String()}
+			p := &Params{ClientID: uuid.NewString(), ClientSecret: uuid.NewString(), ResourceURL: tc.r.String()}
 
 			client, err := newClient(p, clientServiceM)
 
 			must.NoError(t, err)
 
-			err = client.ReloadConfig()
+			err = client.ReloadConfig(context.Background())
 			if tc.err != nil {
 				must.Error(t, err)
 				return
@@ -197,13 +206,156 @@ func TestLoadConfig(t *testing.T) {
 			params := clientServiceM.params
 			must.Eq(t, tc.r.ID, params.ClusterID)
 
-			endpoint, err := client.MetricsEndpoint()
+			cfg, err := client.TelemetryConfig(context.Background())
 			must.NoError(t, err)
-			must.Eq(t, tc.endpoint, endpoint)
-
-			filters, err := client.MetricFilters()
-			must.NoError(t, err)
-			must.Eq(t, tc.filters, filters)
+			must.Eq(t, tc.endpoint, cfg.Endpoint)
+			must.Eq(t, tc.filters, cfg.IncludeList)
 		})
 	}
 }
+
+func Test_RotateCredentials(t *testing.T) {
+	server, err := fakehcp.Start("127.0.0.1:0", fakehcp.Config{ClientID: "cid", ClientSecret: "csec"})
+	must.NoError(t, err)
+	t.Cleanup(server.Close)
+	for k, v := range server.Env() {
+		t.Setenv(k, v)
+	}
+
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "client_secret")
+	must.NoError(t, os.WriteFile(secretFile, []byte("csec"), 0o600))
+
+	client, err := New(&Params{
+		ClientID:         "cid",
+		ClientSecretFile: secretFile,
+		ResourceURL:      testResource().String(),
+	})
+	must.NoError(t, err)
+	must.NoError(t, client.ReloadConfig(context.Background()))
+
+	// the previous secret and its tokens are no longer accepted.
+	server.SetCredentials("cid", "rotated")
+	must.Error(t, client.ReloadConfig(context.Background()))
+
+	must.NoError(t, os.WriteFile(secretFile, []byte("rotated"), 0o600))
+	must.NoError(t, client.ReloadConfig(context.Background()))
+}
+
+func Test_WorkloadIdentity(t *testing.T) {
+	const provider = "iam/project/00000000-0000-0000-0000-000000000001/service-principal/collector/" +
+		"workload-identity-provider/k8s"
+	server, err := fakehcp.Start("127.0.0.1:0", fakehcp.Config{
+		ClientID:                 "cid",
+		ClientSecret:             "csec",
+		WorkloadIdentityProvider: provider,
+		WorkloadIdentityToken:    "jwt",
+	})
+	must.NoError(t, err)
+	t.Cleanup(server.Close)
+	for k, v := range server.Env() {
+		t.Setenv(k, v)
+	}
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	must.NoError(t, os.WriteFile(tokenFile, []byte("jwt\n"), 0o600))
+
+	client, err := New(&Params{
+		ResourceURL:      testResource().String(),
+		WorkloadIdentity: WorkloadIdentity{ProviderResourceName: provider, TokenFile: tokenFile},
+	})
+	must.NoError(t, err)
+	must.NoError(t, client.ReloadConfig(context.Background()))
+
+	// a token that can't be exchanged fails the request.
+	client, err = New(&Params{
+		ResourceURL:      testResource().String(),
+		WorkloadIdentity: WorkloadIdentity{ProviderResourceName: provider + "-other", TokenFile: tokenFile},
+	})
+	must.NoError(t, err)
+	must.Error(t, client.ReloadConfig(context.Background()))
+}
+
+// countingClientService counts the requests it serves with the response of the MockClientService.
+type countingClientService struct {
+	MockClientService
+	calls atomic.Int32
+}
+
+func (c *countingClientService) AgentTelemetryConfig(params *consul_telemetry_service.AgentTelemetryConfigParams,
+	authInfo runtime.ClientAuthInfoWriter,
+	opts ...consul_telemetry_service.ClientOption) (*consul_telemetry_service.AgentTelemetryConfigOK, error) {
+	c.calls.Add(1)
+	return c.MockClientService.AgentTelemetryConfig(params, authInfo, opts...)
+}
+
+// blockingClientService blocks the requests until their context is done.
+type blockingClientService struct{}
+
+func (blockingClientService) AgentTelemetryConfig(params *consul_telemetry_service.AgentTelemetryConfigParams,
+	_ runtime.ClientAuthInfoWriter,
+	_ ...consul_telemetry_service.ClientOption) (*consul_telemetry_service.AgentTelemetryConfigOK, error) {
+	<-params.Context.Done()
+	return nil, params.Context.Err()
+}
+
+func Test_TelemetryConfigConcurrent(t *testing.T) {
+	service := &countingClientService{
+		MockClientService: MockClientService{
+			MockResponse: &consul_telemetry_service.AgentTelemetryConfigOK{
+				Payload: &models.HashicorpCloudConsulTelemetry20230414AgentTelemetryConfigResponse{
+					TelemetryConfig: &models.HashicorpCloudConsulTelemetry20230414TelemetryConfig{
+						Endpoint: "https://global.metrics.com",
+						Labels:   map[string]string{"cluster": "name"},
+						Metrics: &models.HashicorpCloudConsulTelemetry20230414TelemetryMetricsConfig{
+							IncludeList: []string{"a"},
+						},
+					},
+				},
+			},
+		},
+	}
+	client, err := newClient(&Params{ResourceURL: testResource().String()}, service)
+	must.NoError(t, err)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			cfg, err := client.TelemetryConfig(ctx)
+			test.NoError(t, err)
+			test.Eq(t, "https://global.metrics.com", cfg.Endpoint)
+			// the snapshot is a copy, changing it doesn't change the client's configuration.
+			cfg.Labels["cluster"] = "changed"
+			cfg.IncludeList[0] = "changed"
+		}()
+		go func() {
+			defer wg.Done()
+			test.NoError(t, client.ReloadConfig(ctx))
+		}()
+	}
+	wg.Wait()
+
+	cfg, err := client.TelemetryConfig(ctx)
+	must.NoError(t, err)
+	must.Eq(t, map[string]string{"cluster": "name"}, cfg.Labels)
+	must.Eq(t, []string{"a"}, cfg.IncludeList)
+	// the reloads are serialized, and the first retrieval is shared by the concurrent callers.
+	must.Between(t, 10, int(service.calls.Load()), 11)
+}
+
+func Test_ReloadConfigTimeout(t *testing.T) {
+	client, err := newClient(&Params{ResourceURL: testResource().String()}, blockingClientService{})
+	must.NoError(t, err)
+	client.requestTimeout = 10 * time.Millisecond
+
+	err = client.ReloadConfig(context.Background())
+	must.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.TelemetryConfig(ctx)
+	must.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcp
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Credentials are the HCP service principal credentials. The ClientIDFile and ClientSecretFile, when set, take
+// precedence over the ClientID and ClientSecret and are read on every Load so rotated credentials are picked up,
+// like the files of a mounted Kubernetes secret or a Vault Agent template.
+type Credentials struct {
+	ClientID         string
+	ClientSecret     string
+	ClientIDFile     string
+	ClientSecretFile string
+	// WorkloadIdentity, when enabled, is used instead of the client credentials.
+	WorkloadIdentity WorkloadIdentity
+}
+
+// Load returns the client id and secret, reading them from their files when they are set. Surrounding whitespace
+// is trimmed from the file contents.
+func (c Credentials) Load() (clientID, clientSecret string, err error) {
+	clientID, err = valueOrFile(c.ClientID, c.ClientIDFile)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read the client id: %w", err)
+	}
+	clientSecret, err = valueOrFile(c.ClientSecret, c.ClientSecretFile)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read the client secret: %w", err)
+	}
+	return clientID, clientSecret, nil
+}
+
+func valueOrFile(value, file string) (string, error) {
+	if file == "" {
+		return value, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_CredentialsLoad(t *testing.T) {
+	dir := t.TempDir()
+	idFile := filepath.Join(dir, "client_id")
+	secretFile := filepath.Join(dir, "client_secret")
+	must.NoError(t, os.WriteFile(idFile, []byte("file-id\n"), 0o600))
+	must.NoError(t, os.WriteFile(secretFile, []byte("  file-secret\n"), 0o600))
+
+	clientID, clientSecret, err := Credentials{ClientID: "id", ClientSecret: "secret"}.Load()
+	must.NoError(t, err)
+	must.Eq(t, "id", clientID)
+	must.Eq(t, "secret", clientSecret)
+
+	clientID, clientSecret, err = Credentials{
+		ClientID:         "id",
+		ClientIDFile:     idFile,
+		ClientSecretFile: secretFile,
+	}.Load()
+	must.NoError(t, err)
+	must.Eq(t, "file-id", clientID)
+	must.Eq(t, "file-secret", clientSecret)
+
+	_, _, err = Credentials{ClientID: "id", ClientSecretFile: filepath.Join(dir, "missing")}.Load()
+	must.ErrorContains(t, err, "client secret")
+}
@@ -0,0 +1,11 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package fakehcp implements a local stand-in for the parts of HCP the collector talks to: the OAuth2 token
+// endpoint, the workload identity token exchange, the AgentTelemetryConfig endpoint and the OTLP metrics ingest
+// endpoint. It lets the HCP pipeline, including the authentication extensions, the HCP filters and the resource
+// labels, run end-to-end in integration tests and on developer laptops.
+//
+// The server serves TLS with a self-signed certificate since the HCP SDK requires an https auth URL. Env returns
+// the environment variables pointing the collector at the server.
+package fakehcp
@@ -0,0 +1,305 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakehcp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/hashicorp/hcp-sdk-go/clients/cloud-consul-telemetry-gateway/preview/2023-04-14/models"
+)
+
+const (
+	tokenPath  = "/oauth2/token"
+	configPath = "/ctgw/2023-04-14/organizations/{organization}/projects/{project}/clusters/{cluster}/agent/telemetry_config"
+	ingestPath = "/v1/metrics"
+
+	// exchangePrefix and exchangeSuffix surround the workload identity provider resource name, which contains
+	// slashes, in the token exchange path.
+	exchangePrefix = "/2019-12-10/"
+	exchangeSuffix = ":exchange-token"
+
+	protobufContentType = "application/x-protobuf"
+	jsonContentType     = "application/json"
+
+	// resourceIDHeader is the header the collector identifies its HCP resource with when exporting metrics.
+	resourceIDHeader = "x-hcp-resource-id"
+)
+
+// Config is the telemetry configuration the fake HCP returns and the credentials it accepts.
+type Config struct {
+	// ClientID and ClientSecret are the only credentials the token endpoint accepts. Any credentials are
+	// accepted when they are empty.
+	ClientID     string
+	ClientSecret string
+	// WorkloadIdentityProvider is the resource name of the only workload identity provider tokens can be exchanged
+	// with, for the WorkloadIdentityToken. Token exchange is disabled when it is empty.
+	WorkloadIdentityProvider string
+	WorkloadIdentityToken    string
+	// IncludeList are the metric filters of the AgentTelemetryConfig.
+	IncludeList []string
+	// Labels are the labels of the AgentTelemetryConfig the collector sets as resource attributes.
+	Labels map[string]string
+}
+
+// Export is a request received by the metrics ingest endpoint.
+type Export struct {
+	// ResourceID is the HCP resource the collector exported the metrics for.
+	ResourceID string
+	Metrics    pmetric.Metrics
+}
+
+// Server is a running fake HCP.
+type Server struct {
+	cfg    Config
+	server *httptest.Server
+
+	mu      sync.Mutex
+	tokens  map[string]bool
+	exports []Export
+}
+
+// Start serves a fake HCP on the address until it is closed. An address with port 0 picks a free port.
+func Start(addr string, cfg Config) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	s := &Server{
+		cfg:    cfg,
+		tokens: make(map[string]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+tokenPath, s.token)
+	mux.HandleFunc("POST "+exchangePrefix, s.exchangeToken)
+	mux.Handle("POST "+configPath, s.authenticated(s.telemetryConfig))
+	mux.Handle("POST "+ingestPath, s.authenticated(s.ingest))
+
+	s.server = httptest.NewUnstartedServer(mux)
+	s.server.Listener = ln
+	s.server.StartTLS()
+	return s, nil
+}
+
+// Close stops serving.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Address is the host:port the server listens on.
+func (s *Server) Address() string {
+	return s.server.Listener.Addr().String()
+}
+
+// Env returns the environment variables pointing the HCP client, the oauth2client extension and the HCP exporter
+// of the collector at the server. TLS verification is skipped since the certificate is self-signed.
+func (s *Server) Env() map[string]string {
+	return map[string]string{
+		"HCP_AUTH_URL":      "https://" + s.Address(),
+		"HCP_AUTH_TLS":      "insecure",
+		"HCP_API_ADDRESS":   s.Address(),
+		"HCP_API_TLS":       "insecure",
+		"OTLP_EXPORTER_TLS": "insecure",
+	}
+}
+
+// SetCredentials rotates the credentials the token endpoint accepts and revokes the tokens issued so far.
+func (s *Server) SetCredentials(clientID, clientSecret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cfg.ClientID = clientID
+	s.cfg.ClientSecret = clientSecret
+	s.tokens = make(map[string]bool)
+}
+
+// Exports returns the metrics received so far.
+func (s *Server) Exports() []Export {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exports := make([]Export, len(s.exports))
+	copy(exports, s.exports)
+	return exports
+}
+
+// token implements the OAuth2 client credentials grant.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if grant := r.PostForm.Get("grant_type"); grant != "client_credentials" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	s.mu.Lock()
+	valid := s.cfg.ClientID == "" || (clientID == s.cfg.ClientID && clientSecret == s.cfg.ClientSecret)
+	s.mu.Unlock()
+	if !valid {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	token, err := s.issueToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+// exchangeToken implements the HCP IAM exchange of a workload identity token for an access token.
+func (s *Server) exchangeToken(w http.ResponseWriter, r *http.Request) {
+	provider, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, exchangePrefix), exchangeSuffix)
+	if !ok || s.cfg.WorkloadIdentityProvider == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req struct {
+		JWTToken string `json:"jwt_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if provider != s.cfg.WorkloadIdentityProvider || req.JWTToken != s.cfg.WorkloadIdentityToken {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid workload identity token"})
+		return
+	}
+
+	token, err := s.issueToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":        token,
+		"access_token_expiry": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+}
+
+// issueToken returns a new access token accepted by the authenticated endpoints.
+func (s *Server) issueToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	s.mu.Lock()
+	s.tokens[token] = true
+	s.mu.Unlock()
+	return token, nil
+}
+
+// authenticated only calls the handler for requests with a token issued by the token endpoint.
+func (s *Server) authenticated(handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		s.mu.Lock()
+		valid := ok && s.tokens[token]
+		s.mu.Unlock()
+
+		if !valid {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// telemetryConfig returns the AgentTelemetryConfig of every cluster. The metrics are exported to the server.
+func (s *Server) telemetryConfig(w http.ResponseWriter, _ *http.Request) {
+	endpoint := "https://" + s.Address()
+	writeJSON(w, http.StatusOK, &models.HashicorpCloudConsulTelemetry20230414AgentTelemetryConfigResponse{
+		TelemetryConfig: &models.HashicorpCloudConsulTelemetry20230414TelemetryConfig{
+			Endpoint: endpoint,
+			Labels:   s.cfg.Labels,
+			Metrics: &models.HashicorpCloudConsulTelemetry20230414TelemetryMetricsConfig{
+				Endpoint:    endpoint,
+				IncludeList: s.cfg.IncludeList,
+			},
+		},
+	})
+}
+
+// ingest records OTLP metrics sent as protobuf or JSON.
+func (s *Server) ingest(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := pmetricotlp.NewExportRequest()
+	contentType := r.Header.Get("Content-Type")
+	switch contentType {
+	case protobufContentType:
+		err = req.UnmarshalProto(body)
+	case jsonContentType:
+		err = req.UnmarshalJSON(body)
+	default:
+		http.Error(w, fmt.Sprintf("unsupported content type %q", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.exports = append(s.exports, Export{
+		ResourceID: r.Header.Get(resourceIDHeader),
+		Metrics:    req.Metrics(),
+	})
+	s.mu.Unlock()
+
+	resp := pmetricotlp.NewExportResponse()
+	var out []byte
+	if contentType == protobufContentType {
+		out, err = resp.MarshalProto()
+	} else {
+		out, err = resp.MarshalJSON()
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(out)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", jsonContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fakehcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
+)
+
+const resourceURL = "organization/00000000-0000-0000-0000-000000000000/project/" +
+	"00000000-0000-0000-0000-000000000001/hashicorp.consul.cluster/otel-cluster"
+
+func startServer(t *testing.T, cfg Config) *Server {
+	t.Helper()
+
+	s, err := Start("127.0.0.1:0", cfg)
+	must.NoError(t, err)
+	t.Cleanup(s.Close)
+	for k, v := range s.Env() {
+		t.Setenv(k, v)
+	}
+	return s
+}
+
+func TestServer_TelemetryConfig(t *testing.T) {
+	s := startServer(t, Config{
+		ClientID:     "cid",
+		ClientSecret: "csec",
+		IncludeList:  []string{"^cluster\\."},
+		Labels:       map[string]string{"cluster": "otel-cluster"},
+	})
+
+	client, err := hcp.New(&hcp.Params{ClientID: "cid", ClientSecret: "csec", ResourceURL: resourceURL})
+	must.NoError(t, err)
+	must.NoError(t, client.ReloadConfig(context.Background()))
+
+	cfg, err := client.TelemetryConfig(context.Background())
+	must.NoError(t, err)
+	must.Eq(t, "https://"+s.Address(), cfg.Endpoint)
+	must.Eq(t, []string{"^cluster\\."}, cfg.IncludeList)
+	must.Eq(t, map[string]string{"cluster": "otel-cluster"}, cfg.Labels)
+}
+
+func TestServer_InvalidCredentials(t *testing.T) {
+	startServer(t, Config{ClientID: "cid", ClientSecret: "csec"})
+
+	client, err := hcp.New(&hcp.Params{ClientID: "cid", ClientSecret: "wrong", ResourceURL: resourceURL})
+	must.NoError(t, err)
+	must.Error(t, client.ReloadConfig(context.Background()))
+}
+
+func TestServer_Ingest(t *testing.T) {
+	s := startServer(t, Config{})
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("cluster.upstream_rq")
+	body, err := pmetricotlp.NewExportRequestFromMetrics(md).MarshalProto()
+	must.NoError(t, err)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	post := func(token string) int {
+		req, err := http.NewRequest(http.MethodPost, "https://"+s.Address()+ingestPath, bytes.NewReader(body))
+		must.NoError(t, err)
+		req.Header.Set("Content-Type", protobufContentType)
+		req.Header.Set(resourceIDHeader, resourceURL)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(req)
+		must.NoError(t, err)
+		must.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	must.Eq(t, http.StatusUnauthorized, post(""))
+	must.SliceEmpty(t, s.Exports())
+
+	s.mu.Lock()
+	s.tokens["token"] = true
+	s.mu.Unlock()
+	must.Eq(t, http.StatusOK, post("token"))
+
+	exports := s.Exports()
+	must.SliceLen(t, 1, exports)
+	must.Eq(t, resourceURL, exports[0].ResourceID)
+	must.Eq(t, "cluster.upstream_rq", exports[0].Metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+}
@@ -3,6 +3,8 @@
 
 package hcp
 
+import "context"
+
 // MockClient fulfills the TelemetryClient interface and returns static values. Used for testing.
 type MockClient struct {
 	MockMetricsEndpoint  string
@@ -13,26 +15,14 @@ type MockClient struct {
 
 var _ TelemetryClient = (*MockClient)(nil)
 
-// MetricsEndpoint returns the provided metrics endpoint. Will never error.
-func (m *MockClient) MetricsEndpoint() (string, error) {
-	if m.Err != nil {
-		return "", m.Err
-	}
-	return m.MockMetricsEndpoint, nil
-}
-
-// MetricFilters returns the provided metric inclusion filters. Will never error.
-func (m *MockClient) MetricFilters() ([]string, error) {
-	if m.Err != nil {
-		return nil, m.Err
-	}
-	return m.MockMetricFilters, nil
-}
-
-// MetricAttributes returns the provided metric inclusion filters. Will never error.
-func (m *MockClient) MetricAttributes() (map[string]string, error) {
+// TelemetryConfig returns the provided telemetry configuration, or the provided error.
+func (m *MockClient) TelemetryConfig(_ context.Context) (TelemetryConfig, error) {
 	if m.Err != nil {
-		return nil, m.Err
+		return TelemetryConfig{}, m.Err
 	}
-	return m.MockMetricAttributes, nil
+	return TelemetryConfig{
+		Endpoint:    m.MockMetricsEndpoint,
+		Labels:      m.MockMetricAttributes,
+		IncludeList: m.MockMetricFilters,
+	}.clone(), nil
 }
@@ -4,6 +4,10 @@
 package hcp
 
 import (
+	"context"
+	"maps"
+	"slices"
+
 	"github.com/go-openapi/runtime"
 
 	"github.com/hashicorp/hcp-sdk-go/clients/cloud-consul-telemetry-gateway/preview/2023-04-14/client/consul_telemetry_service"
@@ -12,9 +16,28 @@ import (
 // TelemetryClient is a high level client for the AgentTelemetryConfig.
 // It abstracts the interaction with HCP to retrieve the AgentTelemetryConfig.
 type TelemetryClient interface {
-	MetricsEndpoint() (string, error)
-	MetricFilters() ([]string, error)
-	MetricAttributes() (map[string]string, error)
+	// TelemetryConfig returns a snapshot of the telemetry configuration, retrieving it when it has not been yet.
+	TelemetryConfig(ctx context.Context) (TelemetryConfig, error)
+}
+
+// TelemetryConfig is a snapshot of the telemetry configuration retrieved from HCP. Its values are copies, so they
+// stay consistent with each other when the configuration is reloaded.
+type TelemetryConfig struct {
+	// Endpoint is the endpoint the metrics are exported to.
+	Endpoint string
+	// Labels are the attributes set on the exported metrics.
+	Labels map[string]string
+	// IncludeList are the regular expressions of the metric names that are exported.
+	IncludeList []string
+}
+
+// clone returns a deep copy of the configuration.
+func (c TelemetryConfig) clone() TelemetryConfig {
+	return TelemetryConfig{
+		Endpoint:    c.Endpoint,
+		Labels:      maps.Clone(c.Labels),
+		IncludeList: slices.Clone(c.IncludeList),
+	}
 }
 
 // ClientService is a paired down interface for the global-network-manager-service that retrieves the
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// exchangeTokenPath is the HCP IAM path a workload identity token is exchanged for an access token on.
+const exchangeTokenPath = "/2019-12-10/%s:exchange-token"
+
+// WorkloadIdentity authenticates with HCP by exchanging a workload identity token, like a Kubernetes projected
+// service account token or a Nomad workload identity, for an access token of the service principal the workload
+// identity provider belongs to.
+type WorkloadIdentity struct {
+	// ProviderResourceName is the resource name of the HCP workload identity provider, like
+	// iam/project/<project id>/service-principal/<name>/workload-identity-provider/<name>.
+	ProviderResourceName string
+	// TokenFile is the file the workload identity token is read from. It is read on every exchange so the token
+	// can be rotated.
+	TokenFile string
+}
+
+// IsEnabled reports whether the workload identity is configured.
+func (w WorkloadIdentity) IsEnabled() bool {
+	return w.ProviderResourceName != ""
+}
+
+// TokenSource returns an oauth2.TokenSource exchanging the workload identity token with the HCP API at apiURL,
+// like https://api.cloud.hashicorp.com. The access tokens are reused until they expire.
+func (w WorkloadIdentity) TokenSource(apiURL string, httpClient *http.Client) oauth2.TokenSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return oauth2.ReuseTokenSource(nil, &workloadIdentityTokenSource{
+		identity:   w,
+		url:        strings.TrimSuffix(apiURL, "/") + fmt.Sprintf(exchangeTokenPath, w.ProviderResourceName),
+		httpClient: httpClient,
+	})
+}
+
+type workloadIdentityTokenSource struct {
+	identity   WorkloadIdentity
+	url        string
+	httpClient *http.Client
+}
+
+type exchangeTokenRequest struct {
+	JWTToken string `json:"jwt_token"`
+}
+
+type exchangeTokenResponse struct {
+	AccessToken       string    `json:"access_token"`
+	AccessTokenExpiry time.Time `json:"access_token_expiry"`
+}
+
+// Token exchanges the workload identity token for an access token.
+func (s *workloadIdentityTokenSource) Token() (*oauth2.Token, error) {
+	b, err := os.ReadFile(s.identity.TokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the workload identity token: %w", err)
+	}
+
+	body, err := json.Marshal(exchangeTokenRequest{JWTToken: strings.TrimSpace(string(b))})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange the workload identity token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("failed to exchange the workload identity token: %s: %s",
+			resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var exchanged exchangeTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&exchanged); err != nil {
+		return nil, fmt.Errorf("failed to decode the exchanged token: %w", err)
+	}
+	if exchanged.AccessToken == "" {
+		return nil, fmt.Errorf("failed to exchange the workload identity token: no access token returned")
+	}
+
+	return &oauth2.Token{
+		AccessToken: exchanged.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      exchanged.AccessTokenExpiry,
+	}, nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_WorkloadIdentityTokenSource(t *testing.T) {
+	const provider = "iam/project/p/service-principal/sp/workload-identity-provider/wip"
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	var exchanges int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exchanges++
+		must.Eq(t, "/2019-12-10/"+provider+":exchange-token", r.URL.Path)
+
+		var req exchangeTokenRequest
+		must.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.JWTToken != "jwt" {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		must.NoError(t, json.NewEncoder(w).Encode(exchangeTokenResponse{
+			AccessToken:       "access",
+			AccessTokenExpiry: expiry,
+		}))
+	}))
+	t.Cleanup(server.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	w := WorkloadIdentity{ProviderResourceName: provider, TokenFile: tokenFile}
+
+	// the token file is missing.
+	_, err := w.TokenSource(server.URL, nil).Token()
+	must.ErrorContains(t, err, "failed to read the workload identity token")
+
+	must.NoError(t, os.WriteFile(tokenFile, []byte("wrong"), 0o600))
+	_, err = w.TokenSource(server.URL, nil).Token()
+	must.ErrorContains(t, err, "401 Unauthorized: invalid token")
+
+	must.NoError(t, os.WriteFile(tokenFile, []byte(" jwt\n"), 0o600))
+	exchanges = 0
+	ts := w.TokenSource(server.URL+"/", nil)
+	for i := 0; i < 2; i++ {
+		token, err := ts.Token()
+		must.NoError(t, err)
+		must.Eq(t, "access", token.AccessToken)
+		must.Eq(t, "Bearer", token.TokenType)
+		must.True(t, expiry.Equal(token.Expiry))
+	}
+	// the access token is reused until it expires.
+	must.Eq(t, 1, exchanges)
+}
@@ -5,22 +5,39 @@ package otel
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/featuregate"
 	"go.opentelemetry.io/collector/otelcol"
+	"golang.org/x/crypto/hkdf"
 
+	"github.com/hashicorp/consul-telemetry-collector/extensions/healthcheckextension"
 	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/processors"
+	hcpprovider "github.com/hashicorp/consul-telemetry-collector/internal/otel/providers/hcp"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
 	"github.com/hashicorp/consul-telemetry-collector/internal/version"
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/redactionprocessor"
 	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver"
 )
 
 const defaultMetricsPort = 9090
+const defaultScrapeInterval = time.Minute
 const defaultBatchTimeout = time.Minute
 const defaultEnvoyPort = envoyreceiver.DefaultGRPCPort
 
+// hashKeyInfo binds the key derived from the HCP client secret to the hashing of the default HCP redaction.
+const hashKeyInfo = "consul-telemetry-collector hcp redaction hash key"
+
 // Collector is an interface that is satisfied by the otelcol.Collector struct.
 // This allows us to wrap the opentelemetry collector and not necessarily run it ourselves.
 type Collector interface {
@@ -31,15 +48,72 @@ type Collector interface {
 
 // CollectorCfg is the configuration needed to start the collector.
 type CollectorCfg struct {
-	ClientID          string
-	ClientSecret      string
+	ClientID     string
+	ClientSecret string
+	// ClientIDFile and ClientSecretFile, when set, are read for the HCP credentials instead of the ClientID and
+	// ClientSecret. The collector reloads its configuration when they change.
+	ClientIDFile     string
+	ClientSecretFile string
+	// WorkloadIdentity, when enabled, authenticates with HCP by exchanging a workload identity token instead of
+	// with the client credentials.
+	WorkloadIdentity  hcp.WorkloadIdentity
 	ResourceID        string
 	Client            hcp.TelemetryClient
 	ForwarderEndpoint string
 	ExporterConfig    *config.ExporterConfig
 	MetricsPort       int
 	EnvoyPort         int
-	BatchTimeout      time.Duration
+	// MetricsAddress is the host:port the internal metrics are served on. Defaults to localhost:<MetricsPort>.
+	MetricsAddress string
+	// MetricsLevel is the level of the internal metrics: none, basic, normal or detailed. Defaults to detailed.
+	MetricsLevel string
+	// ScrapeInterval is how often the collector scrapes its own metrics.
+	ScrapeInterval time.Duration
+	// SelfMetricsExporter, when set, receives the collector's own metrics instead of the envoy metrics pipelines.
+	SelfMetricsExporter *config.ExporterConfig
+	// CardinalityLimit, when set, caps the unique series per metric and per proxy in the envoy metrics pipelines.
+	CardinalityLimit *cardinalityprocessor.Config
+	// HCPAggregation, when set, aggregates the metrics exported to HCP across resource attributes.
+	HCPAggregation *aggregationprocessor.Config
+	// ExternalAggregation, when set, aggregates the metrics exported to the external exporter across resource
+	// attributes.
+	ExternalAggregation *aggregationprocessor.Config
+	// HCPRedaction redacts the attributes of the metrics exported to HCP. When it is not set, the node.id is hashed
+	// with a key derived from the client secret and resource ID, so its hashes only change with the secret.
+	HCPRedaction *redactionprocessor.Config
+	// ExternalRedaction, when set, redacts the attributes of the metrics exported to the external exporter.
+	ExternalRedaction *redactionprocessor.Config
+	// Renames, when set, renames envoy metrics and attributes, for example to OpenTelemetry semantic conventions.
+	Renames *processors.Renames
+	// TagExtraction, when set, extracts the dimensions envoy embeds in stat names into data point attributes.
+	TagExtraction *envoyreceiver.TagExtractionConfig
+	// StripUnitSuffixes removes the unit suffixes, like _bytes or _ms, from the envoy metric names.
+	StripUnitSuffixes bool
+	// ResourceAttributes, when set, selects the envoy node properties, like node metadata and locality, set as
+	// resource attributes.
+	ResourceAttributes *envoyreceiver.ResourceAttributesConfig
+	// LocalFilter, when set, filters the envoy metrics with the user's allow and deny lists. It applies to every
+	// pipeline, on top of the HCP filters in the HCP pipeline.
+	LocalFilter *processors.LocalFilter
+	// Routes, when set, export the envoy metrics of a Consul partition or namespace to the exporter of its route
+	// instead of the ExporterConfig.
+	Routes []config.Route
+	// HCPResources, when set, export the envoy metrics matching their attributes to other HCP resources than the
+	// ResourceID.
+	HCPResources []hcpprovider.Resource
+	BatchTimeout time.Duration
+	// HealthCheckEndpoint enables the health check extension on this address when set.
+	HealthCheckEndpoint string
+	// PprofEndpoint enables the pprof extension on this address when set.
+	PprofEndpoint string
+	// ZPagesEndpoint enables the zpages extension on this address when set.
+	ZPagesEndpoint string
+	// Proxy, when enabled, is the proxy the connections of the exporters and auth extensions go through.
+	Proxy *proxy.Config
+	// LogLevel is the hclog level name the collector logs at. Defaults to info.
+	LogLevel string
+	// LogJSON outputs the collector logs as JSON in the same format as hclog.
+	LogJSON bool
 }
 
 func (c *CollectorCfg) init() {
@@ -54,6 +128,14 @@ func (c *CollectorCfg) init() {
 	if c.EnvoyPort == 0 {
 		c.EnvoyPort = defaultEnvoyPort
 	}
+
+	if c.MetricsAddress == "" {
+		c.MetricsAddress = fmt.Sprintf("localhost:%d", c.MetricsPort)
+	}
+
+	if c.ScrapeInterval == 0 {
+		c.ScrapeInterval = defaultScrapeInterval
+	}
 }
 
 const otelFeatureGate = "telemetry.useOtelForInternalMetrics"
@@ -65,12 +147,20 @@ func NewCollector(cfg CollectorCfg) (Collector, error) {
 		return nil, err
 	}
 
-	factories, err := components()
+	factories, err := components(readinessChecks(cfg)...)
 	if err != nil {
 		return nil, err
 	}
 
 	cfg.init()
+	if cfg.HCPRedaction == nil && (cfg.ResourceID != "" || len(cfg.HCPResources) > 0) {
+		hashKey, err := hcpHashKey(cfg)
+		if err != nil {
+			return nil, err
+		}
+		redaction := processors.DefaultHCPRedaction(hashKey)
+		cfg.HCPRedaction = &redaction
+	}
 
 	provider, err := newProvider(cfg)
 	if err != nil {
@@ -86,9 +176,72 @@ func NewCollector(cfg CollectorCfg) (Collector, error) {
 		},
 		DisableGracefulShutdown: true,
 		ConfigProvider:          provider,
-		LoggingOptions:          nil,
+		LoggingOptions:          loggingOptions(cfg),
 		SkipSettingGRPCLogger:   false,
 	}
 
 	return otelcol.NewCollector(set)
 }
+
+// hcpHashKey derives the key of the default HCP redaction from the client secret and ID of the HCP resource, or of
+// the first HCP resource with a client secret, so the hashed node.ids stay the same across restarts and between the
+// collectors of the resource until the secret is rotated. With workload identity there is no secret to derive it
+// from, and the HCP redaction must be configured instead.
+func hcpHashKey(cfg CollectorCfg) (string, error) {
+	credentials := []hcpprovider.Resource{{
+		ResourceID: cfg.ResourceID,
+		Credentials: hcp.Credentials{
+			ClientSecret:     cfg.ClientSecret,
+			ClientSecretFile: cfg.ClientSecretFile,
+		},
+	}}
+	credentials = append(credentials, cfg.HCPResources...)
+
+	for _, r := range credentials {
+		if r.ResourceID == "" {
+			continue
+		}
+		_, secret, err := hcp.Credentials{
+			ClientSecret:     r.Credentials.ClientSecret,
+			ClientSecretFile: r.Credentials.ClientSecretFile,
+		}.Load()
+		if err != nil {
+			return "", fmt.Errorf("failed to derive the redaction hash key: %w", err)
+		}
+		if secret == "" {
+			continue
+		}
+
+		key := make([]byte, 32)
+		kdf := hkdf.New(sha256.New, []byte(secret), []byte(r.ResourceID), []byte(hashKeyInfo))
+		if _, err := io.ReadFull(kdf, key); err != nil {
+			return "", fmt.Errorf("failed to derive the redaction hash key: %w", err)
+		}
+		return hex.EncodeToString(key), nil
+	}
+	return "", errors.New("the HCP redaction must be configured to hash the node.id without a client secret")
+}
+
+// readinessChecks returns the checks, in addition to the ones configured on the health check extension,
+// that must pass for the collector to be ready. When HCP is enabled the telemetry configuration must have been
+// retrieved.
+func readinessChecks(cfg CollectorCfg) []healthcheckextension.Check {
+	var checks []healthcheckextension.Check
+	if cfg.ResourceID != "" && cfg.Client != nil {
+		checks = append(checks, telemetryConfigCheck(cfg.Client, "HCP telemetry configuration"))
+	}
+	for _, r := range cfg.HCPResources {
+		checks = append(checks, telemetryConfigCheck(r.Client,
+			fmt.Sprintf("HCP telemetry configuration of resource %q", r.Name)))
+	}
+	return checks
+}
+
+func telemetryConfigCheck(client hcp.TelemetryClient, what string) healthcheckextension.Check {
+	return func(ctx context.Context) error {
+		if _, err := client.TelemetryConfig(ctx); err != nil {
+			return fmt.Errorf("%s has not been retrieved: %w", what, err)
+		}
+		return nil
+	}
+}
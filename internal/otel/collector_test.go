@@ -5,6 +5,8 @@ package otel
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -15,6 +17,7 @@ import (
 	"go.opentelemetry.io/collector/otelcol"
 
 	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
+	hcpprovider "github.com/hashicorp/consul-telemetry-collector/internal/otel/providers/hcp"
 	"github.com/hashicorp/hcp-sdk-go/resource"
 )
 
@@ -110,6 +113,44 @@ func Test_New(t *testing.T) {
 	}
 }
 
+func Test_hcpHashKey(t *testing.T) {
+	key, err := hcpHashKey(CollectorCfg{ResourceID: "resource-1", ClientSecret: "secret-1"})
+	must.NoError(t, err)
+	must.Eq(t, 64, len(key))
+
+	// the key is the same across restarts, and read from the secret file like the credentials.
+	secretFile := filepath.Join(t.TempDir(), "client_secret")
+	must.NoError(t, os.WriteFile(secretFile, []byte("secret-1\n"), 0o600))
+	fromFile, err := hcpHashKey(CollectorCfg{ResourceID: "resource-1", ClientSecretFile: secretFile})
+	must.NoError(t, err)
+	must.Eq(t, key, fromFile)
+
+	otherSecret, err := hcpHashKey(CollectorCfg{ResourceID: "resource-1", ClientSecret: "secret-2"})
+	must.NoError(t, err)
+	must.NotEq(t, key, otherSecret)
+	otherResource, err := hcpHashKey(CollectorCfg{ResourceID: "resource-2", ClientSecret: "secret-1"})
+	must.NoError(t, err)
+	must.NotEq(t, key, otherResource)
+
+	// with workload identity, the key comes from the first HCP resource with a client secret.
+	fromResource, err := hcpHashKey(CollectorCfg{
+		ResourceID:       "resource-2",
+		WorkloadIdentity: hcp.WorkloadIdentity{ProviderResourceName: "provider", TokenFile: "token"},
+		HCPResources: []hcpprovider.Resource{{
+			ResourceID:  "resource-1",
+			Credentials: hcp.Credentials{ClientSecret: "secret-1"},
+		}},
+	})
+	must.NoError(t, err)
+	must.Eq(t, key, fromResource)
+
+	_, err = hcpHashKey(CollectorCfg{
+		ResourceID:       "resource-1",
+		WorkloadIdentity: hcp.WorkloadIdentity{ProviderResourceName: "provider", TokenFile: "token"},
+	})
+	must.ErrorContains(t, err, "the HCP redaction must be configured")
+}
+
 type containsFunc[T any] func(T) bool
 
 func (c containsFunc[T]) Contains(s T) bool {
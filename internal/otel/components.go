@@ -4,18 +4,20 @@
 package otel
 
 import (
-	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/oauth2clientauthextension"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/basicauthextension"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/bearertokenauthextension"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/pprofextension"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sattributesprocessor"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricstransformprocessor"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourceprocessor"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
+	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/loggingexporter"
-	"go.opentelemetry.io/collector/exporter/otlpexporter"
-	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
 	"go.opentelemetry.io/collector/extension"
 	"go.opentelemetry.io/collector/extension/ballastextension"
+	"go.opentelemetry.io/collector/extension/zpagesextension"
 	"go.opentelemetry.io/collector/otelcol"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/batchprocessor"
@@ -23,10 +25,19 @@ import (
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver"
 
+	"github.com/hashicorp/consul-telemetry-collector/connectors/routingconnector"
+	"github.com/hashicorp/consul-telemetry-collector/exporters/otlpexporter"
+	"github.com/hashicorp/consul-telemetry-collector/exporters/otlphttpexporter"
+	"github.com/hashicorp/consul-telemetry-collector/extensions/healthcheckextension"
+	"github.com/hashicorp/consul-telemetry-collector/extensions/oauth2clientextension"
+	"github.com/hashicorp/consul-telemetry-collector/extensions/workloadidentityextension"
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/redactionprocessor"
 	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver"
 )
 
-func components() (otelcol.Factories, error) {
+func components(checks ...healthcheckextension.Check) (otelcol.Factories, error) {
 	var err error
 	factories := otelcol.Factories{}
 
@@ -46,14 +57,23 @@ func components() (otelcol.Factories, error) {
 		metricstransformprocessor.NewFactory(),
 		filterprocessor.NewFactory(),
 		k8sattributesprocessor.NewFactory(),
+		cardinalityprocessor.NewFactory(),
+		aggregationprocessor.NewFactory(),
+		redactionprocessor.NewFactory(),
 	)
 	if err != nil {
 		return otelcol.Factories{}, err
 	}
 
 	factories.Extensions, err = extension.MakeFactoryMap(
-		oauth2clientauthextension.NewFactory(),
+		oauth2clientextension.NewFactory(),
+		bearertokenauthextension.NewFactory(),
+		basicauthextension.NewFactory(),
 		ballastextension.NewFactory(),
+		healthcheckextension.NewFactory(checks...),
+		pprofextension.NewFactory(),
+		zpagesextension.NewFactory(),
+		workloadidentityextension.NewFactory(),
 	)
 	if err != nil {
 		return otelcol.Factories{}, err
@@ -68,5 +88,12 @@ func components() (otelcol.Factories, error) {
 		return otelcol.Factories{}, err
 	}
 
+	factories.Connectors, err = connector.MakeFactoryMap(
+		routingconnector.NewFactory(),
+	)
+	if err != nil {
+		return otelcol.Factories{}, err
+	}
+
 	return factories, nil
 }
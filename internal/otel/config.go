@@ -9,6 +9,7 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/otelcol"
 
+	hcpclient "github.com/hashicorp/consul-telemetry-collector/internal/hcp"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/providers"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/providers/external"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/providers/hcp"
@@ -16,21 +17,49 @@ import (
 
 func newProvider(cfg CollectorCfg) (otelcol.ConfigProvider, error) {
 	uris := []string{"external:"}
-	if cfg.ResourceID != "" {
+	// The hcp provider builds the pipelines of all the HCP resources, with an empty resource when there is only
+	// the HCPResources.
+	if cfg.ResourceID != "" || len(cfg.HCPResources) > 0 {
 		uris = append(uris, fmt.Sprintf("hcp:%s", cfg.ResourceID))
 	}
 
 	params := providers.SharedParams{
-		BatchTimeout: cfg.BatchTimeout,
-		MetricsPort:  cfg.MetricsPort,
-		EnvoyPort:    cfg.EnvoyPort,
+		BatchTimeout:        cfg.BatchTimeout,
+		MetricsAddress:      cfg.MetricsAddress,
+		MetricsLevel:        cfg.MetricsLevel,
+		ScrapeInterval:      cfg.ScrapeInterval,
+		SelfMetricsExporter: cfg.SelfMetricsExporter,
+		CardinalityLimit:    cfg.CardinalityLimit,
+		HCPAggregation:      cfg.HCPAggregation,
+		ExternalAggregation: cfg.ExternalAggregation,
+		HCPRedaction:        cfg.HCPRedaction,
+		ExternalRedaction:   cfg.ExternalRedaction,
+		Renames:             cfg.Renames,
+		TagExtraction:       cfg.TagExtraction,
+		StripUnitSuffixes:   cfg.StripUnitSuffixes,
+		ResourceAttributes:  cfg.ResourceAttributes,
+		Routes:              cfg.Routes,
+		LocalFilter:         cfg.LocalFilter,
+		EnvoyPort:           cfg.EnvoyPort,
+		HealthCheckEndpoint: cfg.HealthCheckEndpoint,
+		PprofEndpoint:       cfg.PprofEndpoint,
+		ZPagesEndpoint:      cfg.ZPagesEndpoint,
+		Proxy:               cfg.Proxy,
+		LogLevel:            cfg.LogLevel,
+		LogJSON:             cfg.LogJSON,
 	}
 
 	resolver := confmap.ResolverSettings{
 		URIs: uris,
 		Providers: makeMapProvidersMap(
 			external.NewProvider(cfg.ExporterConfig, params),
-			hcp.NewProvider(cfg.ExporterConfig, cfg.Client, cfg.ClientID, cfg.ClientSecret, params),
+			hcp.NewProvider(cfg.ExporterConfig, cfg.Client, hcpclient.Credentials{
+				ClientID:         cfg.ClientID,
+				ClientSecret:     cfg.ClientSecret,
+				ClientIDFile:     cfg.ClientIDFile,
+				ClientSecretFile: cfg.ClientSecretFile,
+				WorkloadIdentity: cfg.WorkloadIdentity,
+			}, cfg.HCPResources, params),
 		),
 		Converters: []confmap.Converter{},
 	}
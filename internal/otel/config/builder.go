@@ -4,54 +4,196 @@
 package config
 
 import (
+	"net"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configauth"
+	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/service/pipelines"
 
 	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/connectors"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/exporters"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/extensions"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/processors"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/receivers"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/redactionprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver"
 )
 
 // Params are the inputs to the configuration building process. Only some config requires
 // these inputs.
 type Params struct {
-	ExporterConfig    *ExporterConfig
-	Client            hcp.TelemetryClient
+	ExporterConfig *ExporterConfig
+	// TelemetryConfig is the snapshot of the HCP telemetry configuration the HCP pipeline is built from.
+	TelemetryConfig   *hcp.TelemetryConfig
 	ClientID          string
 	ClientSecret      string
 	ResourceID        string
 	BatchTimeout      time.Duration
-	MetricsPort       int
 	EnvoyListenerPort int
+	// WorkloadIdentity, when enabled, authenticates the HCP exporter with the workload identity extension instead
+	// of the oauth2client extension.
+	WorkloadIdentity hcp.WorkloadIdentity
+	// MetricsAddress is the host:port the collector's internal metrics are served on.
+	MetricsAddress string
+	// MetricsLevel is the level name of the collector's internal metrics. The collector does not scrape
+	// its own metrics when it is none.
+	MetricsLevel string
+	// ScrapeInterval is how often the collector scrapes its own metrics.
+	ScrapeInterval time.Duration
+	// SelfMetricsExporter, when set, exports the collector's own metrics in a dedicated pipeline instead of
+	// the envoy metrics pipelines.
+	SelfMetricsExporter *ExporterConfig
+	// CardinalityLimit, when set, caps the unique series of the envoy metrics pipelines.
+	CardinalityLimit *cardinalityprocessor.Config
+	// HCPAggregation, when set, aggregates the metrics of the HCP pipeline across resource attributes.
+	HCPAggregation *aggregationprocessor.Config
+	// ExternalAggregation, when set, aggregates the metrics of the external pipeline across resource attributes.
+	ExternalAggregation *aggregationprocessor.Config
+	// HCPRedaction, when set, redacts the attributes of the metrics of the HCP pipeline. The collector sets it to
+	// processors.DefaultHCPRedaction when none is configured.
+	HCPRedaction *redactionprocessor.Config
+	// ExternalRedaction, when set, redacts the attributes of the metrics of the external pipeline.
+	ExternalRedaction *redactionprocessor.Config
+	// LocalFilter, when set, filters the envoy metrics pipelines with the user's allow and deny lists.
+	LocalFilter *processors.LocalFilter
+	// Renames, when set, renames the envoy metrics and attributes of the envoy metrics pipelines.
+	Renames *processors.Renames
+	// TagExtraction, when set, extracts the dimensions envoy embeds in stat names into attributes.
+	TagExtraction *envoyreceiver.TagExtractionConfig
+	// StripUnitSuffixes removes the unit suffixes from the envoy metric names.
+	StripUnitSuffixes bool
+	// ResourceAttributes, when set, selects the envoy node properties set as resource attributes.
+	ResourceAttributes *envoyreceiver.ResourceAttributesConfig
+	// Routes, when set, send the metrics of the pipeline to the exporter of the route matching their partition or
+	// namespace. The metrics no route matches go to the ExporterConfig.
+	Routes []Route
+	// HCPResources, when set, are HCP resources the envoy metrics matching their attributes are exported to instead
+	// of the HCP resource of the params. The metrics no resource matches go to the HCP resource of the params, or
+	// are not exported to HCP when there is none.
+	HCPResources []HCPResource
+	// HealthCheckEndpoint is the listen address of the health check extension.
+	HealthCheckEndpoint string
+	// PprofEndpoint is the listen address of the pprof extension.
+	PprofEndpoint string
+	// ZPagesEndpoint is the listen address of the zpages extension.
+	ZPagesEndpoint string
+	// Proxy, when enabled, is the proxy the connections of the exporters and auth extensions go through.
+	Proxy *proxy.Config
 }
 
 // ExporterConfig holds a dynamic exporter configuration and corresponding component.ID
 type ExporterConfig struct {
 	ID       component.ID
 	Exporter *exporters.ExporterConfig
+	// Authenticator, when set, is the auth extension the exporter authenticates with.
+	Authenticator *Authenticator
 }
 
+// Authenticator holds the configuration of an auth extension and its component.ID.
+type Authenticator struct {
+	ID     component.ID
+	Config any
+}
+
+// exporterCfg returns the exporter configuration referencing the Authenticator, if there is one.
+func (e *ExporterConfig) exporterCfg() *exporters.ExporterConfig {
+	if e.Authenticator == nil {
+		return e.Exporter
+	}
+	cfg := *e.Exporter
+	cfg.Auth = &configauth.Authentication{AuthenticatorID: e.Authenticator.ID}
+	return &cfg
+}
+
+// Route sends the metrics of the envoy proxies in a Consul admin partition, namespace, or namespace within a
+// partition to its exporter.
+type Route struct {
+	Name      string
+	Partition string
+	Namespace string
+	Exporter  *ExporterConfig
+	// Redaction, when set, redacts the metrics of the route before they are exported to its exporter, after the
+	// redaction of the external pipeline.
+	Redaction *redactionprocessor.Config
+}
+
+// pipelineID is the id of the pipeline exporting the metrics of the route.
+func (r Route) pipelineID() component.ID {
+	return component.NewIDWithName(component.DataTypeMetrics, "route-"+r.Name)
+}
+
+// HCPResource is an HCP resource, with its own telemetry configuration, credentials, filters and labels, the envoy metrics with its
+// resource attribute values are exported to.
+type HCPResource struct {
+	// Name identifies the resource in the ids of its components, like otlphttp/hcp-<name>.
+	Name       string
+	ResourceID string
+	// TelemetryConfig is the snapshot of the HCP telemetry configuration of the resource.
+	TelemetryConfig *hcp.TelemetryConfig
+	ClientID        string
+	ClientSecret    string
+	// WorkloadIdentity, when enabled, authenticates the exporter instead of the ClientID and ClientSecret.
+	WorkloadIdentity hcp.WorkloadIdentity
+	// Attributes are the resource attribute values, like ones set from the node metadata, of the metrics exported
+	// to the resource.
+	Attributes map[string]string
+}
+
+// pipelineID is the id of the pipeline exporting the metrics of the resource.
+func (r HCPResource) pipelineID() component.ID {
+	return component.NewIDWithName(component.DataTypeMetrics, "hcp-"+r.Name)
+}
+
+// params returns the params of the pipeline exporting to the resource. They are the params with the telemetry
+// configuration, credentials and resource ID of the resource.
+func (r HCPResource) params(p *Params) *Params {
+	rp := *p
+	rp.TelemetryConfig = r.TelemetryConfig
+	rp.ClientID = r.ClientID
+	rp.ClientSecret = r.ClientSecret
+	rp.WorkloadIdentity = r.WorkloadIdentity
+	rp.ResourceID = r.ResourceID
+	rp.HCPResources = nil
+	return &rp
+}
+
+// HCPPipelineID is the id of the pipeline exporting to the HCP resource of the params.
+var HCPPipelineID = component.NewIDWithName(component.DataTypeMetrics, "hcp")
+
+// HCPRoutingPipelineID is the id of the pipeline sending the envoy metrics to the pipelines of the HCP resources.
+var HCPRoutingPipelineID = component.NewIDWithName(component.DataTypeMetrics, "hcp-routing")
+
+// DefaultRoutePipelineID is the id of the pipeline exporting the metrics no route matches to the ExporterConfig.
+var DefaultRoutePipelineID = component.NewIDWithName(component.DataTypeMetrics, "default")
+
+// SelfMetricsPipelineID is the id of the pipeline exporting the collector's own metrics to the SelfMetricsExporter.
+var SelfMetricsPipelineID = component.NewIDWithName(component.DataTypeMetrics, "self")
+
 // PipelineConfigBuilder defines a basic list of pipeline component IDs for a service.PipelineConfig.
 func PipelineConfigBuilder(p *Params) pipelines.PipelineConfig {
 	baseCfg := pipelines.PipelineConfig{
-		Processors: []component.ID{
-			processors.MemoryLimiterID,
-			// add your processors here
-			processors.BatchProcessorID,
-		},
-		Receivers: []component.ID{receivers.EnvoyReceiverID, receivers.PrometheusReceiverID},
+		Processors: ProcessorBuilder(OptionalProcessors(p)...),
+		Receivers:  []component.ID{receivers.EnvoyReceiverID},
 		Exporters: []component.ID{
 			exporters.LoggingExporterID,
 		},
 	}
 
-	includeHCPPipeline := p.ClientID != "" && p.ClientSecret != "" && p.Client != nil
-	if includeHCPPipeline {
+	// the collector's own metrics are mixed in with the envoy metrics unless they have a dedicated pipeline.
+	if p.selfMetricsEnabled() && p.SelfMetricsExporter == nil {
+		baseCfg.Receivers = append(baseCfg.Receivers, receivers.PrometheusReceiverID)
+	}
+
+	if p.includeHCPPipeline() {
 		baseCfg.Exporters = append(baseCfg.Exporters, exporters.HCPExporterID)
+	} else if p.routed() {
+		baseCfg.Exporters = append(baseCfg.Exporters, connectors.RoutingConnectorID)
 	} else if p.ExporterConfig != nil {
 		baseCfg.Exporters = append(baseCfg.Exporters, p.ExporterConfig.ID)
 	}
@@ -59,6 +201,95 @@ func PipelineConfigBuilder(p *Params) pipelines.PipelineConfig {
 	return baseCfg
 }
 
+// SelfMetricsPipelineConfig defines the pipeline scraping the collector's own metrics and exporting them to the
+// SelfMetricsExporter. It returns false if there is no such pipeline.
+func SelfMetricsPipelineConfig(p *Params) (pipelines.PipelineConfig, bool) {
+	if !p.selfMetricsEnabled() || p.SelfMetricsExporter == nil {
+		return pipelines.PipelineConfig{}, false
+	}
+
+	return pipelines.PipelineConfig{
+		Receivers:  []component.ID{receivers.PrometheusReceiverID},
+		Processors: ProcessorBuilder(),
+		Exporters:  []component.ID{p.SelfMetricsExporter.ID},
+	}, true
+}
+
+// includeHCPPipeline reports whether the params build the pipeline exporting to HCP.
+func (p *Params) includeHCPPipeline() bool {
+	return ((p.ClientID != "" && p.ClientSecret != "") || p.WorkloadIdentity.IsEnabled()) && p.TelemetryConfig != nil
+}
+
+// hcpAuthenticator returns the ID of the extension authenticating the HCP exporter.
+func (p *Params) hcpAuthenticator() component.ID {
+	if p.WorkloadIdentity.IsEnabled() {
+		return extensions.WorkloadIdentityID
+	}
+	return extensions.OauthClientID
+}
+
+// routed reports whether the pipeline the params build exports through the routing connector.
+func (p *Params) routed() bool {
+	return !p.includeHCPPipeline() && len(p.Routes) > 0
+}
+
+// exporter returns the ExporterConfig, SelfMetricsExporter or route exporter with the id, or nil if there is none.
+func (p *Params) exporter(id component.ID) *ExporterConfig {
+	if p.ExporterConfig != nil && p.ExporterConfig.ID == id {
+		return p.ExporterConfig
+	}
+	if p.SelfMetricsExporter != nil && p.SelfMetricsExporter.ID == id {
+		return p.SelfMetricsExporter
+	}
+	return p.routeExporter(id)
+}
+
+// routeExporter returns the exporter of the route with the id, or nil if there is none.
+func (p *Params) routeExporter(id component.ID) *ExporterConfig {
+	for _, r := range p.Routes {
+		if r.Exporter != nil && r.Exporter.ID == id {
+			return r.Exporter
+		}
+	}
+	return nil
+}
+
+// aggregation returns the id and configuration of the aggregation processor of the pipeline the params build.
+// The configuration is nil when the pipeline is not aggregated.
+func (p *Params) aggregation() (component.ID, *aggregationprocessor.Config) {
+	if p.includeHCPPipeline() {
+		return processors.AggregationHCPID, p.HCPAggregation
+	}
+	return processors.AggregationExternalID, p.ExternalAggregation
+}
+
+// redaction returns the id and configuration of the redaction processor of the pipeline the params build. The
+// configuration is nil when the pipeline is not redacted.
+func (p *Params) redaction() (component.ID, *redactionprocessor.Config) {
+	if p.includeHCPPipeline() {
+		return processors.RedactionHCPID, p.HCPRedaction
+	}
+	return processors.RedactionExternalID, p.ExternalRedaction
+}
+
+func (p *Params) selfMetricsEnabled() bool {
+	return MetricsLevel(p.MetricsLevel) != configtelemetry.LevelNone
+}
+
+// metricsTarget returns the host:port to reach the internal metrics on. Metrics served on every interface
+// are reached through localhost.
+func (p *Params) metricsTarget() string {
+	host, port, err := net.SplitHostPort(p.MetricsAddress)
+	if err != nil {
+		return p.MetricsAddress
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		return net.JoinHostPort("localhost", port)
+	}
+	return p.MetricsAddress
+}
+
 // Opts is a variadic type passed in as a way  of manipulating a list of components.
 type Opts func([]component.ID) []component.ID
 
@@ -68,11 +299,68 @@ func WithExtOauthClientID(ext []component.ID) []component.ID {
 	return append(ext, extensions.OauthClientID)
 }
 
+// WithExtWorkloadIdentityID is an Opt function to add the workload identity extension id to the list of extensions.
+// NOTE: this extension will require the params to specify a WorkloadIdentity.
+func WithExtWorkloadIdentityID(ext []component.ID) []component.ID {
+	return append(ext, extensions.WorkloadIdentityID)
+}
+
+// WithExtHealthCheck is an Opt function to add the health check extension to the list of extensions.
+func WithExtHealthCheck(ext []component.ID) []component.ID {
+	return append(ext, extensions.HealthCheckID)
+}
+
+// WithExtPprof is an Opt function to add the pprof extension to the list of extensions.
+func WithExtPprof(ext []component.ID) []component.ID {
+	return append(ext, extensions.PprofID)
+}
+
+// WithExtZPages is an Opt function to add the zpages extension to the list of extensions.
+func WithExtZPages(ext []component.ID) []component.ID {
+	return append(ext, extensions.ZPagesID)
+}
+
 // WithFilterProcessor is an Opt function to add the filter processor to a list of processors.
 func WithFilterProcessor(procesors []component.ID) []component.ID {
 	return append(procesors, processors.FilterProcessorID)
 }
 
+// WithLocalFilter is an Opt function to add the filter processor applying the user's filter to a list of processors.
+func WithLocalFilter(prcs []component.ID) []component.ID {
+	return append(prcs, processors.LocalFilterID)
+}
+
+// WithAggregation returns an Opt function to add the aggregation processor with the id to a list of processors.
+func WithAggregation(id component.ID) Opts {
+	return func(prcs []component.ID) []component.ID {
+		return append(prcs, id)
+	}
+}
+
+// WithRedaction returns an Opt function to add the redaction processor with the id to a list of processors.
+func WithRedaction(id component.ID) Opts {
+	return func(prcs []component.ID) []component.ID {
+		return append(prcs, id)
+	}
+}
+
+// WithMetricsTransform is an Opt function to add the metrics transform processor to a list of processors.
+func WithMetricsTransform(prcs []component.ID) []component.ID {
+	return append(prcs, processors.MetricsTransformID)
+}
+
+// WithCardinalityLimiter is an Opt function to add the cardinality limiter processor to a list of processors.
+func WithCardinalityLimiter(prcs []component.ID) []component.ID {
+	return append(prcs, processors.CardinalityLimiterID)
+}
+
+// withID returns an Opt function adding the component id to a list of components.
+func withID(id component.ID) Opts {
+	return func(ids []component.ID) []component.ID {
+		return append(ids, id)
+	}
+}
+
 // WithResourceProcessor adds the resource processor to a list of processors. It should go after the filter processor to ensure that we do not operate on signals that we won't forward.
 func WithResourceProcessor(prcs []component.ID) []component.ID {
 	return append(prcs, processors.ResourceProcessorID)
@@ -89,6 +377,49 @@ func ExtensionBuilder(opts ...Opts) []component.ID {
 	return base
 }
 
+// OptionalExtensions returns the Opts adding the extensions that are enabled by the params. They are
+// shared by every provider.
+func OptionalExtensions(p *Params) []Opts {
+	opts := []Opts{}
+	if p.HealthCheckEndpoint != "" {
+		opts = append(opts, WithExtHealthCheck)
+	}
+	if p.PprofEndpoint != "" {
+		opts = append(opts, WithExtPprof)
+	}
+	if p.ZPagesEndpoint != "" {
+		opts = append(opts, WithExtZPages)
+	}
+	return opts
+}
+
+// OptionalProcessors returns the Opts adding the processors that are enabled by the params to the envoy metrics
+// pipelines. They are shared by every provider.
+func OptionalProcessors(p *Params) []Opts {
+	opts := []Opts{}
+	// filtering first avoids processing the metrics that are dropped.
+	if p.LocalFilter != nil {
+		opts = append(opts, WithLocalFilter)
+	}
+	// limiting before aggregating lets the cardinality limiter count the series per node.id, which the aggregation
+	// drops by default, and bounds the series the aggregation holds.
+	if p.CardinalityLimit != nil {
+		opts = append(opts, WithCardinalityLimiter)
+	}
+	if id, aggregation := p.aggregation(); aggregation != nil {
+		opts = append(opts, WithAggregation(id))
+	}
+	// renaming last keeps the metric names the HCP filter and the limits match on.
+	if p.Renames != nil {
+		opts = append(opts, WithMetricsTransform)
+	}
+	// redacting after renaming matches the attribute names that are exported.
+	if id, redaction := p.redaction(); redaction != nil {
+		opts = append(opts, WithRedaction(id))
+	}
+	return opts
+}
+
 // ProcessorBuilder returns a list of processor IDs.
 // The provided IDs inserted between the memory limiter and batch processor.
 func ProcessorBuilder(opts ...Opts) []component.ID {
@@ -11,6 +11,8 @@ import (
 	"go.opentelemetry.io/collector/service"
 	"go.opentelemetry.io/collector/service/pipelines"
 
+	"github.com/hashicorp/consul-telemetry-collector/connectors/routingconnector"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/connectors"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/exporters"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/extensions"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/processors"
@@ -56,11 +58,11 @@ func (c *Config) EnrichWithPipelineCfg(
 	pipelineID component.ID,
 ) error {
 	var merr *multierror.Error
-	// Receivers
-	err := buildComponents(c.Receivers, pCfg.Receivers, p)
+	// Receivers, connectors are built with the pipelines they route to.
+	err := buildComponents(c.Receivers, withoutConnectors(pCfg.Receivers), p)
 	merr = multierror.Append(merr, err)
 	// Exporters
-	err = buildExporterComponents(c.Exporters, pCfg.Exporters, p)
+	err = buildExporterComponents(c.Exporters, withoutConnectors(pCfg.Exporters), p)
 	merr = multierror.Append(merr, err)
 	// Processors
 	err = buildComponents(c.Processors, pCfg.Processors, p)
@@ -69,10 +71,178 @@ func (c *Config) EnrichWithPipelineCfg(
 	if merr.ErrorOrNil() != nil {
 		return merr
 	}
+	c.enrichWithExporterAuth(pCfg.Exporters, p)
 	c.Service.Pipelines[pipelineID] = &pCfg
 	return nil
 }
 
+// enrichWithExporterAuth adds the auth extensions the exporters of the params authenticate with. An extension is
+// only added once, even if its exporter is in several pipelines. The token requests of the oauth2client extensions
+// go through the proxy when it is enabled.
+func (c *Config) enrichWithExporterAuth(exporterIDs []component.ID, p *Params) {
+	for _, id := range exporterIDs {
+		e := p.exporter(id)
+		if e == nil || e.Authenticator == nil {
+			continue
+		}
+		if _, ok := c.Extensions[e.Authenticator.ID]; ok {
+			continue
+		}
+		authCfg := e.Authenticator.Config
+		if oauthCfg, ok := authCfg.(*extensions.OauthClientConfig); ok && p.Proxy.Enabled() {
+			proxied := *oauthCfg
+			proxied.Proxy = p.Proxy
+			authCfg = &proxied
+		}
+		c.Extensions[e.Authenticator.ID] = authCfg
+		c.Service.Extensions = append(c.Service.Extensions, e.Authenticator.ID)
+	}
+}
+
+// EnrichWithSelfMetricsPipeline adds the pipeline exporting the collector's own metrics when the params
+// configure a SelfMetricsExporter.
+func (c *Config) EnrichWithSelfMetricsPipeline(p *Params) error {
+	pCfg, ok := SelfMetricsPipelineConfig(p)
+	if !ok {
+		return nil
+	}
+	return c.EnrichWithPipelineCfg(pCfg, p, SelfMetricsPipelineID)
+}
+
+// EnrichWithRoutePipelines adds the routing connector and the pipelines it routes to when the params configure
+// Routes. Each route has a pipeline exporting to its exporter, redacting its metrics first when the route has a
+// redaction, and the metrics no route matches are exported to the ExporterConfig, or dropped if there is none.
+func (c *Config) EnrichWithRoutePipelines(p *Params) error {
+	if !p.routed() {
+		return nil
+	}
+
+	var defaultPipelines []component.ID
+	if p.ExporterConfig != nil {
+		defaultPipelines = []component.ID{DefaultRoutePipelineID}
+		pCfg := routePipelineConfig(p.ExporterConfig.ID)
+		if err := c.EnrichWithPipelineCfg(pCfg, p, DefaultRoutePipelineID); err != nil {
+			return err
+		}
+	}
+
+	routes := make([]routingconnector.Route, 0, len(p.Routes))
+	for _, r := range p.Routes {
+		if r.Exporter == nil {
+			return fmt.Errorf("route %q must specify an exporter", r.Name)
+		}
+		pCfg := routePipelineConfig(r.Exporter.ID)
+		if r.Redaction != nil {
+			redactionID := processors.RedactionRouteIDFor(r.Name)
+			c.Processors[redactionID] = processors.RedactionCfg(*r.Redaction)
+			pCfg.Processors = []component.ID{redactionID}
+		}
+		if err := c.EnrichWithPipelineCfg(pCfg, p, r.pipelineID()); err != nil {
+			return err
+		}
+		routes = append(routes, routingconnector.Route{
+			Partition: r.Partition,
+			Namespace: r.Namespace,
+			Pipelines: []component.ID{r.pipelineID()},
+		})
+	}
+
+	c.Connectors[connectors.RoutingConnectorID] = connectors.RoutingConnectorCfg(routes, defaultPipelines)
+	return nil
+}
+
+// EnrichWithHCPResources adds the pipelines exporting to the HCP resources of the params and the routing connector
+// sending the envoy metrics to them, when the params configure HCPResources. The metrics no resource matches are
+// sent to the HCP pipeline of the params, which must then receive them from the routing connector, or dropped if
+// there is none.
+func (c *Config) EnrichWithHCPResources(p *Params) error {
+	if len(p.HCPResources) == 0 {
+		return nil
+	}
+
+	var defaultPipelines []component.ID
+	if p.includeHCPPipeline() {
+		defaultPipelines = []component.ID{HCPPipelineID}
+	}
+
+	routes := make([]routingconnector.Route, 0, len(p.HCPResources))
+	for _, r := range p.HCPResources {
+		if err := c.enrichWithHCPResource(r, p); err != nil {
+			return fmt.Errorf("failed to build HCP resource %q: %w", r.Name, err)
+		}
+		routes = append(routes, routingconnector.Route{
+			Attributes: r.Attributes,
+			Pipelines:  []component.ID{r.pipelineID()},
+		})
+	}
+	c.Connectors[connectors.HCPRoutingConnectorID] = connectors.RoutingConnectorCfg(routes, defaultPipelines)
+
+	routingCfg := pipelines.PipelineConfig{
+		Receivers: PipelineConfigBuilder(p).Receivers,
+		Exporters: []component.ID{connectors.HCPRoutingConnectorID},
+	}
+	return c.EnrichWithPipelineCfg(routingCfg, p, HCPRoutingPipelineID)
+}
+
+// enrichWithHCPResource adds the pipeline exporting to the HCP resource and its authentication extension. The
+// pipeline has the processors of the HCP pipeline with the filters and labels of the resource.
+func (c *Config) enrichWithHCPResource(r HCPResource, p *Params) error {
+	if r.TelemetryConfig == nil {
+		return errors.New("a telemetry configuration must be specified")
+	}
+	rp := r.params(p)
+
+	authID := extensions.OauthClientIDFor(r.Name)
+	if r.WorkloadIdentity.IsEnabled() {
+		authID = extensions.WorkloadIdentityIDFor(r.Name)
+		c.Extensions[authID] = extensions.WorkloadIdentityCfg(r.WorkloadIdentity.ProviderResourceName,
+			r.WorkloadIdentity.TokenFile, p.Proxy)
+	} else {
+		if r.ClientID == "" || r.ClientSecret == "" {
+			return errors.New("a client id and secret or a workload identity must be specified")
+		}
+		c.Extensions[authID] = extensions.OauthClientCfg(r.ClientID, r.ClientSecret, p.Proxy)
+	}
+	c.Service.Extensions = append(c.Service.Extensions, authID)
+
+	exporterID := exporters.HCPExporterIDFor(r.Name)
+	c.Exporters[exporterID] = exporters.OtlpExporterHCPCfg(r.TelemetryConfig.Endpoint, r.ResourceID, authID, p.Proxy)
+
+	filterID := processors.FilterProcessorIDFor(r.Name)
+	resourceID := processors.ResourceProcessorIDFor(r.Name)
+	c.Processors[filterID] = processors.FilterProcessorCfg(*r.TelemetryConfig)
+	c.Processors[resourceID] = processors.ResourcesProcessorCfg(*r.TelemetryConfig)
+
+	processorOpts := append([]Opts{withID(filterID)}, OptionalProcessors(rp)...)
+	processorOpts = append(processorOpts, withID(resourceID))
+	pCfg := pipelines.PipelineConfig{
+		Receivers:  []component.ID{connectors.HCPRoutingConnectorID},
+		Processors: ProcessorBuilder(processorOpts...),
+		Exporters:  []component.ID{exporters.LoggingExporterID, exporterID},
+	}
+	return c.EnrichWithPipelineCfg(pCfg, rp, r.pipelineID())
+}
+
+// routePipelineConfig defines a pipeline receiving metrics from the routing connector. The metrics are already
+// processed by the pipeline they are routed from so it has no processors, other than the redaction of a route.
+func routePipelineConfig(exporter component.ID) pipelines.PipelineConfig {
+	return pipelines.PipelineConfig{
+		Receivers: []component.ID{connectors.RoutingConnectorID},
+		Exporters: []component.ID{exporter},
+	}
+}
+
+// withoutConnectors returns the componentIDs that are not connectors.
+func withoutConnectors(componentIDs []component.ID) []component.ID {
+	ids := make([]component.ID, 0, len(componentIDs))
+	for _, id := range componentIDs {
+		if id.Type() != connectors.RoutingConnectorID.Type() {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // EnrichWithExtensions adds the specific configurations for a given list of extension IDs.
 // The parameters are sometimes required to build an extension so they should be passed through.
 func (c *Config) EnrichWithExtensions(
@@ -127,34 +297,47 @@ func buildExporterComponents(
 }
 
 func buildExporters(id component.ID, p *Params) (any, error) {
+	if p.SelfMetricsExporter != nil && id == p.SelfMetricsExporter.ID {
+		return buildOtlpExporter(p.SelfMetricsExporter, p)
+	}
+	if e := p.routeExporter(id); e != nil {
+		return buildOtlpExporter(e, p)
+	}
+
 	switch id {
 	// exporters
 	case exporters.LoggingExporterID:
 		return exporters.LogExporterCfg(), nil
 	case exporters.HCPExporterID:
-		if p.Client == nil {
-			return nil, errors.New("parameters must specify a client to build HPC exporter config")
-		}
-		metricsEndpoint, err := p.Client.MetricsEndpoint()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get metrics endpoint: %w", err)
+		if p.TelemetryConfig == nil {
+			return nil, errors.New("parameters must specify a telemetry configuration to build HPC exporter config")
 		}
 
-		return exporters.OtlpExporterHCPCfg(metricsEndpoint, p.ResourceID, extensions.OauthClientID), nil
-	case exporters.BaseOtlpExporterID:
-		cfg, err := exporters.OtlpExporterCfg(p.ExporterConfig.Exporter)
-		if err != nil {
-			return nil, err
+		return exporters.OtlpExporterHCPCfg(p.TelemetryConfig.Endpoint, p.ResourceID, p.hcpAuthenticator(),
+			p.Proxy), nil
+	case exporters.BaseOtlpExporterID, exporters.GRPCOtlpExporterID:
+		return buildOtlpExporter(p.ExporterConfig, p)
+	default:
+		return nil, fmt.Errorf("unsupported component id: %s", id)
+	}
+}
+
+// buildOtlpExporter builds an otlp exporter, like the one the collector's own metrics or a route are sent to. Its
+// connections go through the proxy when it is enabled.
+func buildOtlpExporter(e *ExporterConfig, p *Params) (any, error) {
+	switch e.ID.Type() {
+	case exporters.BaseOtlpExporterID.Type(), exporters.GRPCOtlpExporterID.Type():
+		exporterCfg := *e.exporterCfg()
+		if p.Proxy.Enabled() {
+			exporterCfg.Proxy = p.Proxy
 		}
-		return cfg.ToStringMap(), nil
-	case exporters.GRPCOtlpExporterID:
-		cfg, err := exporters.OtlpExporterCfg(p.ExporterConfig.Exporter)
+		cfg, err := exporters.OtlpExporterCfg(&exporterCfg)
 		if err != nil {
 			return nil, err
 		}
 		return cfg.ToStringMap(), nil
 	default:
-		return nil, fmt.Errorf("unsupported component id: %s", id)
+		return nil, fmt.Errorf("unsupported exporter type: %s", e.ID.Type())
 	}
 }
 
@@ -165,18 +348,56 @@ func buildComponent(id component.ID, p *Params) (any, error) {
 	case receivers.OtlpReceiverID:
 		return receivers.OtlpReceiverCfg(), nil
 	case receivers.EnvoyReceiverID:
-		return receivers.EnvoyReceiverCfg(p.EnvoyListenerPort), nil
+		return receivers.EnvoyReceiverCfg(p.EnvoyListenerPort, p.TagExtraction, p.StripUnitSuffixes,
+			p.ResourceAttributes), nil
 	case receivers.PrometheusReceiverID:
-		return receivers.PrometheusReceiverCfg(p.MetricsPort), nil
+		return receivers.PrometheusReceiverCfg(p.metricsTarget(), p.ScrapeInterval), nil
 	// processors
 	case processors.MemoryLimiterID:
 		return processors.MemoryLimiterCfg(), nil
 	case processors.BatchProcessorID:
 		return processors.BatchProcessorCfg(p.BatchTimeout), nil
 	case processors.FilterProcessorID:
-		return processors.FilterProcessorCfg(p.Client), nil
+		if p.TelemetryConfig == nil {
+			return nil, errors.New("parameters must specify a telemetry configuration to build the filter processor")
+		}
+		return processors.FilterProcessorCfg(*p.TelemetryConfig), nil
 	case processors.ResourceProcessorID:
-		return processors.ResourcesProcessorCfg(p.Client), nil
+		if p.TelemetryConfig == nil {
+			return nil, errors.New("parameters must specify a telemetry configuration to build the resource processor")
+		}
+		return processors.ResourcesProcessorCfg(*p.TelemetryConfig), nil
+	case processors.LocalFilterID:
+		if p.LocalFilter == nil {
+			return nil, errors.New("parameters must specify a filter to build the local filter processor")
+		}
+		return processors.LocalFilterCfg(*p.LocalFilter), nil
+	case processors.CardinalityLimiterID:
+		if p.CardinalityLimit == nil {
+			return nil, errors.New("parameters must specify limits to build a cardinality limiter")
+		}
+		return processors.CardinalityLimiterCfg(*p.CardinalityLimit), nil
+	case processors.MetricsTransformID:
+		if p.Renames == nil {
+			return nil, errors.New("parameters must specify renames to build a metrics transform processor")
+		}
+		return processors.MetricsTransformCfg(*p.Renames), nil
+	case processors.AggregationHCPID:
+		if p.HCPAggregation == nil {
+			return nil, errors.New("parameters must specify an aggregation to build the HCP aggregation processor")
+		}
+		return processors.AggregationCfg(*p.HCPAggregation), nil
+	case processors.AggregationExternalID:
+		if p.ExternalAggregation == nil {
+			return nil, errors.New("parameters must specify an aggregation to build the external aggregation processor")
+		}
+		return processors.AggregationCfg(*p.ExternalAggregation), nil
+	case processors.RedactionHCPID, processors.RedactionExternalID:
+		redactionID, redaction := p.redaction()
+		if redactionID != id || redaction == nil {
+			return nil, fmt.Errorf("parameters must specify a redaction to build the %s processor", id)
+		}
+		return processors.RedactionCfg(*redaction), nil
 	// extensions
 	case extensions.BallastID:
 		return extensions.BallastCfg(), nil
@@ -184,7 +405,22 @@ func buildComponent(id component.ID, p *Params) (any, error) {
 		if p.ClientID == "" || p.ClientSecret == "" {
 			return nil, errors.New("parameters must specify a client id and secret to build an Oauth extension")
 		}
-		return extensions.OauthClientCfg(p.ClientID, p.ClientSecret), nil
+		return extensions.OauthClientCfg(p.ClientID, p.ClientSecret, p.Proxy), nil
+	case extensions.WorkloadIdentityID:
+		if !p.WorkloadIdentity.IsEnabled() {
+			return nil, errors.New("parameters must specify a workload identity to build a workload identity extension")
+		}
+		return extensions.WorkloadIdentityCfg(p.WorkloadIdentity.ProviderResourceName, p.WorkloadIdentity.TokenFile,
+			p.Proxy), nil
+	case extensions.HealthCheckID:
+		// exports are tracked through the internal metrics, so the exporters can't be checked if those are
+		// disabled. The agent rejects a health check without internal metrics.
+		return extensions.HealthCheckCfg(p.HealthCheckEndpoint, p.EnvoyListenerPort, p.metricsTarget(),
+			p.selfMetricsEnabled()), nil
+	case extensions.PprofID:
+		return extensions.PprofCfg(p.PprofEndpoint), nil
+	case extensions.ZPagesID:
+		return extensions.ZPagesCfg(p.ZPagesEndpoint), nil
 	default:
 		return nil, fmt.Errorf("unsupported component id: %s", id)
 	}
@@ -0,0 +1,26 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package connectors
+
+import (
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/hashicorp/consul-telemetry-collector/connectors/routingconnector"
+)
+
+// RoutingConnectorID is the component id of the routing connector.
+var RoutingConnectorID component.ID = component.NewID(routingconnector.ID)
+
+// HCPRoutingConnectorID is the component id of the routing connector sending the envoy metrics to the pipelines of
+// the HCP resources.
+var HCPRoutingConnectorID component.ID = component.NewIDWithName(routingconnector.ID, "hcp")
+
+// RoutingConnectorCfg generates the config for a routing connector sending the metrics no route matches to the
+// defaultPipelines.
+func RoutingConnectorCfg(routes []routingconnector.Route, defaultPipelines []component.ID) *routingconnector.Config {
+	return &routingconnector.Config{
+		Routes:           routes,
+		DefaultPipelines: defaultPipelines,
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package connectors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/hashicorp/consul-telemetry-collector/connectors/routingconnector"
+)
+
+func Test_RoutingConnector(t *testing.T) {
+	cfg := RoutingConnectorCfg([]routingconnector.Route{
+		{
+			Partition: "team-a",
+			Namespace: "payments",
+			Pipelines: []component.ID{component.NewIDWithName(component.DataTypeMetrics, "route-team-a")},
+		},
+	}, []component.ID{component.NewIDWithName(component.DataTypeMetrics, "default")})
+	require.NoError(t, cfg.Validate())
+
+	// Marshall the configuration
+	conf := confmap.New()
+	err := conf.Marshal(cfg)
+	require.NoError(t, err)
+
+	// Unmarshall and verify
+	unmarshalledCfg := &routingconnector.Config{}
+	err = conf.Unmarshal(unmarshalledCfg)
+	require.NoError(t, err)
+
+	require.Equal(t, cfg, unmarshalledCfg)
+}
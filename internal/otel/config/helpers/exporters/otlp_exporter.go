@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/types"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
 	"github.com/hashicorp/consul-telemetry-collector/internal/version"
 )
 
@@ -43,6 +44,11 @@ var (
 	GRPCOtlpExporterID = component.NewID(otlpGRPCExporterName)
 )
 
+// HCPExporterIDFor returns the id of the otel exporter of the named HCP resource, otlphttp/hcp-<name>.
+func HCPExporterIDFor(name string) component.ID {
+	return component.NewIDWithName(otlpHTTPExporterName, "hcp-"+name)
+}
+
 // ExporterConfig is a base wrapper around the otlphttpexorter which
 // we cannot use directly since our golden tests can't handle the comparisons unfortunately.
 // https://pkg.go.dev/go.opentelemetry.io/collector/exporter/otlphttpexporter@v0.72.0#section-readme
@@ -62,6 +68,9 @@ type ExporterConfig struct {
 
 	// Timeout is the http request time limit
 	Timeout string `mapstructure:"timeout,omitempty"`
+
+	// Proxy, when set, is the proxy the connections of the exporter go through.
+	Proxy *proxy.Config `mapstructure:"proxy,omitempty"`
 }
 
 // OtlpExporterCfg generates the configuration for a otlp exporter.
@@ -85,8 +94,9 @@ func OtlpExporterCfg(e *ExporterConfig) (*confmap.Conf, error) {
 	return c, nil
 }
 
-// OtlpExporterHCPCfg generates the config for an otlp exporter to HCP.
-func OtlpExporterHCPCfg(endpoint, resourceID string, authID component.ID) *ExporterConfig {
+// OtlpExporterHCPCfg generates the config for an otlp exporter to HCP. Its connections go through the proxy when it is
+// enabled.
+func OtlpExporterHCPCfg(endpoint, resourceID string, authID component.ID, p *proxy.Config) *ExporterConfig {
 	// TODO: unfortunately we can't use the exporter config that comes form the otlphttpexporter.Config
 	// due to unmarshalling issues. This is unfortunate but for now it's not the end of the world to ship our own config. Leaving this here as a reference
 	// to get to the defaultCfg if it's needed.
@@ -106,6 +116,9 @@ func OtlpExporterHCPCfg(endpoint, resourceID string, authID component.ID) *Expor
 		TLSSetting:  tlsConfigForSetting(endpoint),
 		Compression: "none",
 	}
+	if p.Enabled() {
+		cfg.Proxy = p
+	}
 
 	return &cfg
 }
@@ -31,7 +31,7 @@ func Test_OtlpHTTPExporter(t *testing.T) {
 }
 
 func Test_OtlpHTTPExporterHCP(t *testing.T) {
-	cfg := OtlpExporterHCPCfg("foobar", "resource-id", component.NewID("foobarid"))
+	cfg := OtlpExporterHCPCfg("foobar", "resource-id", component.NewID("foobarid"), nil)
 	require.NotNil(t, cfg)
 
 	// Marshall the configuration
@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"go.opentelemetry.io/collector/component"
+)
+
+const basicAuthName = "basicauth"
+
+// BasicAuthIDFor returns the component.ID of the basicauth extension the named exporter authenticates with,
+// basicauth/<name>.
+func BasicAuthIDFor(name string) component.ID {
+	return component.NewIDWithName(basicAuthName, name)
+}
+
+// BasicAuthConfig is a wrapper around the basicauthextension.Config which we cannot use directly since the opaque
+// password gets changed to REDACTED when marshalling.
+//
+//	https://github.com/open-telemetry/opentelemetry-collector-contrib/tree/main/extension/basicauthextension
+type BasicAuthConfig struct {
+	// ClientAuth are the credentials the client authenticates with.
+	ClientAuth BasicAuthClientConfig `mapstructure:"client_auth"`
+}
+
+// BasicAuthClientConfig holds the username and password of a basicauth client.
+type BasicAuthClientConfig struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// BasicAuthCfg returns the basicauth config of a client authenticating with the username and password.
+func BasicAuthCfg(username, password string) *BasicAuthConfig {
+	return &BasicAuthConfig{
+		ClientAuth: BasicAuthClientConfig{
+			Username: username,
+			Password: password,
+		},
+	}
+}
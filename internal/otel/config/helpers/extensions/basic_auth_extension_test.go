@@ -0,0 +1,30 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/basicauthextension"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func Test_BasicAuth(t *testing.T) {
+	cfg := BasicAuthCfg("user", "password")
+	require.Equal(t, "basicauth/otlp-self", BasicAuthIDFor("otlp-self").String())
+
+	// Marshall the configuration
+	conf := confmap.New()
+	require.NoError(t, conf.Marshal(cfg))
+
+	// Unmarshall into the extension's configuration and verify
+	extCfg := basicauthextension.NewFactory().CreateDefaultConfig().(*basicauthextension.Config)
+	require.NoError(t, conf.Unmarshal(extCfg))
+	require.NoError(t, extCfg.Validate())
+	require.Nil(t, extCfg.Htpasswd)
+	require.Equal(t, "user", extCfg.ClientAuth.Username)
+	require.Equal(t, configopaque.String("password"), extCfg.ClientAuth.Password)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"go.opentelemetry.io/collector/component"
+)
+
+const bearerTokenAuthName = "bearertokenauth"
+
+// BearerTokenAuthIDFor returns the component.ID of the bearertokenauth extension the named exporter authenticates
+// with, bearertokenauth/<name>.
+func BearerTokenAuthIDFor(name string) component.ID {
+	return component.NewIDWithName(bearerTokenAuthName, name)
+}
+
+// BearerTokenAuthConfig is a wrapper around the bearertokenauthextension.Config. Only the token file is supported so
+// that the token is never written to the configuration, the extension reloads it when the file changes.
+//
+//	https://github.com/open-telemetry/opentelemetry-collector-contrib/tree/main/extension/bearertokenauthextension
+type BearerTokenAuthConfig struct {
+	// Scheme is the auth scheme of the token. The extension defaults it to Bearer.
+	Scheme string `mapstructure:"scheme,omitempty"`
+
+	// Filename is the file the token is read from.
+	Filename string `mapstructure:"filename"`
+}
+
+// BearerTokenAuthCfg returns the bearertokenauth config sending the token of the file with the scheme.
+func BearerTokenAuthCfg(scheme, filename string) *BearerTokenAuthConfig {
+	return &BearerTokenAuthConfig{
+		Scheme:   scheme,
+		Filename: filename,
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/bearertokenauthextension"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func Test_BearerTokenAuth(t *testing.T) {
+	cfg := BearerTokenAuthCfg("", "/var/run/token")
+	require.Equal(t, "bearertokenauth/otlphttp", BearerTokenAuthIDFor("otlphttp").String())
+
+	// Marshall the configuration
+	conf := confmap.New()
+	require.NoError(t, conf.Marshal(cfg))
+
+	// Unmarshall into the extension's configuration and verify
+	extCfg := bearertokenauthextension.NewFactory().CreateDefaultConfig().(*bearertokenauthextension.Config)
+	require.NoError(t, conf.Unmarshal(extCfg))
+	require.NoError(t, extCfg.Validate())
+	require.Equal(t, "Bearer", extCfg.Scheme)
+	require.Equal(t, "/var/run/token", extCfg.Filename)
+	require.Empty(t, extCfg.BearerToken)
+}
@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/hashicorp/consul-telemetry-collector/extensions/healthcheckextension"
+)
+
+// HealthCheckID is the component id of the health check extension.
+var HealthCheckID component.ID = component.NewID(healthcheckextension.ID)
+
+// HealthCheckCfg generates the config for the health check extension. Readiness requires the envoy
+// listener to be serving and, if checkExporters is set, no exporter to have been failing to send metrics. The
+// metricsTarget is the host:port the collector's internal metrics, which track the exports, are served on.
+func HealthCheckCfg(endpoint string, envoyPort int, metricsTarget string, checkExporters bool) *healthcheckextension.Config {
+	cfg := healthcheckextension.CreateDefaultConfig().(*healthcheckextension.Config)
+	if endpoint != "" {
+		cfg.Endpoint = endpoint
+	}
+	cfg.EnvoyEndpoint = fmt.Sprintf("127.0.0.1:%d", envoyPort)
+
+	if checkExporters {
+		cfg.CheckExporters = true
+		cfg.MetricsEndpoint = fmt.Sprintf("http://%s/metrics", metricsTarget)
+	}
+
+	return cfg
+}
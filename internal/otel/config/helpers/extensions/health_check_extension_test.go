@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/hashicorp/consul-telemetry-collector/extensions/healthcheckextension"
+)
+
+func Test_HealthCheckExtension(t *testing.T) {
+	for name, tc := range map[string]struct {
+		endpoint       string
+		checkExporters bool
+	}{
+		"Default": {},
+		"WithEndpoint": {
+			endpoint: "127.0.0.1:8080",
+		},
+		"WithExporters": {
+			checkExporters: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := HealthCheckCfg(tc.endpoint, 9356, "localhost:9090", tc.checkExporters)
+			require.NotNil(t, cfg)
+			require.NoError(t, cfg.Validate())
+			require.Equal(t, "127.0.0.1:9356", cfg.EnvoyEndpoint)
+			if tc.endpoint != "" {
+				require.Equal(t, tc.endpoint, cfg.Endpoint)
+			}
+			if tc.endpoint == "" {
+				require.Equal(t, "localhost:13133", cfg.Endpoint)
+			}
+			require.Equal(t, tc.checkExporters, cfg.CheckExporters)
+			if tc.checkExporters {
+				require.Equal(t, "http://localhost:9090/metrics", cfg.MetricsEndpoint)
+			}
+
+			// Marshall the configuration
+			conf := confmap.New()
+			err := conf.Marshal(cfg)
+			require.NoError(t, err)
+
+			// Unmarshall and verify
+			unmarshalledCfg := &healthcheckextension.Config{}
+			err = conf.Unmarshal(unmarshalledCfg)
+			require.NoError(t, err)
+
+			require.Equal(t, cfg, unmarshalledCfg)
+		})
+	}
+}
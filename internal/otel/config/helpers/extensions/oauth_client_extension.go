@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/types"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
 )
 
 const (
@@ -29,10 +30,20 @@ const (
 // OauthClientID is the component.ID used by the oauth2client extension.
 var OauthClientID component.ID = component.NewIDWithName(oauth2ClientName, "hcp")
 
-// OauthClientConfig is a base wrapper around the oauth2clientauthextension.Config which
+// OauthClientIDFor returns the component.ID of the oauth2client extension of the named HCP resource,
+// oauth2client/hcp-<name>.
+func OauthClientIDFor(name string) component.ID {
+	return component.NewIDWithName(oauth2ClientName, "hcp-"+name)
+}
+
+// OauthClientExporterIDFor returns the component.ID of the oauth2client extension the named exporter authenticates
+// with, oauth2client/<name>.
+func OauthClientExporterIDFor(name string) component.ID {
+	return component.NewIDWithName(oauth2ClientName, name)
+}
+
+// OauthClientConfig is a base wrapper around the oauth2clientextension.Config which
 // we cannot use directly since the opaque client secret string gets changed to REDACTED when unmarshalling
-//
-//	https://github.com/open-telemetry/opentelemetry-collector-contrib/tree/main/extension/oauth2clientauthextension
 type OauthClientConfig struct {
 
 	// ClientID is the application's ID.
@@ -49,12 +60,19 @@ type OauthClientConfig struct {
 	// See https://datatracker.ietf.org/doc/html/rfc6749#section-3.2
 	TokenURL string `mapstructure:"token_url"`
 
+	// Scopes optionally specifies a list of requested permission scopes.
+	Scopes []string `mapstructure:"scopes,omitempty"`
+
 	// TLSSetting struct exposes TLS client configuration for the underneath client to authorization server.
 	TLSSetting types.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// Proxy, when set, is the proxy the connections to the token endpoint go through.
+	Proxy *proxy.Config `mapstructure:"proxy,omitempty"`
 }
 
-// OauthClientCfg returns a component ID and oauth config.
-func OauthClientCfg(clientID, clientSecret string) *OauthClientConfig {
+// OauthClientCfg returns the oauth config authenticating with HCP. The token requests go through the proxy when it is
+// enabled.
+func OauthClientCfg(clientID, clientSecret string, p *proxy.Config) *OauthClientConfig {
 	authURL, ok := os.LookupEnv(envVarAuthURL)
 	if !ok {
 		authURL = defaultIssuerURL
@@ -62,13 +80,31 @@ func OauthClientCfg(clientID, clientSecret string) *OauthClientConfig {
 
 	authTLSConfig := tlsConfigForSetting()
 
-	return &OauthClientConfig{
+	cfg := &OauthClientConfig{
 		ClientID:       clientID,
 		ClientSecret:   clientSecret,
 		TokenURL:       fmt.Sprintf("%s/oauth2/token", authURL),
 		EndpointParams: url.Values{audienceKey: []string{defaultAudience}},
 		TLSSetting:     authTLSConfig,
 	}
+	if p.Enabled() {
+		cfg.Proxy = p
+	}
+	return cfg
+}
+
+// OauthClientCredentialsCfg returns the oauth config of a client credentials grant with any token endpoint, like
+// the one a custom exporter authenticates with.
+func OauthClientCredentialsCfg(clientID, clientSecret, tokenURL string, scopes []string,
+	endpointParams url.Values,
+) *OauthClientConfig {
+	return &OauthClientConfig{
+		ClientID:       clientID,
+		ClientSecret:   clientSecret,
+		TokenURL:       tokenURL,
+		EndpointParams: endpointParams,
+		Scopes:         scopes,
+	}
 }
 
 func tlsConfigForSetting() types.TLSClientSetting {
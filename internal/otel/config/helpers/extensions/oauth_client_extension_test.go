@@ -4,17 +4,22 @@
 package extensions
 
 import (
+	"net/url"
 	"testing"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/oauth2clientauthextension"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/hashicorp/consul-telemetry-collector/extensions/oauth2clientextension"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
 )
 
 func Test_OauthClient(t *testing.T) {
-	cfg := OauthClientCfg("cid", "csec")
+	cfg := OauthClientCfg("cid", "csec", &proxy.Config{URL: "http://proxy:3128", NoProxy: []string{".internal"}})
 	require.NotNil(t, cfg)
+	require.Equal(t, "http://proxy:3128", cfg.Proxy.URL)
+	require.Nil(t, OauthClientCfg("cid", "csec", &proxy.Config{}).Proxy)
 
 	// Marshall the configuration
 	conf := confmap.New()
@@ -29,11 +34,31 @@ func Test_OauthClient(t *testing.T) {
 	require.Equal(t, cfg, unmarshalledCfg)
 }
 
+func Test_OauthClientCredentials(t *testing.T) {
+	cfg := OauthClientCredentialsCfg("cid", "csec", "https://idp.internal/oauth2/token", []string{"metrics.write"},
+		url.Values{"audience": []string{"collector"}})
+	require.Equal(t, "oauth2client/otlp-route-team-a", OauthClientExporterIDFor("otlp-route-team-a").String())
+
+	// Marshall the configuration
+	conf := confmap.New()
+	require.NoError(t, conf.Marshal(cfg))
+
+	// Unmarshall into the extension's configuration and verify
+	extCfg := oauth2clientextension.NewFactory().CreateDefaultConfig().(*oauth2clientextension.Config)
+	require.NoError(t, conf.Unmarshal(extCfg))
+	require.NoError(t, extCfg.Validate())
+	require.Equal(t, "cid", extCfg.ClientID)
+	require.Equal(t, configopaque.String("csec"), extCfg.ClientSecret)
+	require.Equal(t, "https://idp.internal/oauth2/token", extCfg.TokenURL)
+	require.Equal(t, []string{"metrics.write"}, extCfg.Scopes)
+	require.Equal(t, url.Values{"audience": []string{"collector"}}, extCfg.EndpointParams)
+}
+
 // The purpose of this test is so that if the package ever supports marshaling with an opaque
 // client secret we might be able to use it. Since our configuration is marshaled we can't
 // use it as it gets exported unfortunately.
 func Test_OauthClientPkg(t *testing.T) {
-	cfg := oauth2clientauthextension.Config{
+	cfg := oauth2clientextension.Config{
 		ClientSecret: configopaque.String("foo"),
 	}
 
@@ -42,7 +67,7 @@ func Test_OauthClientPkg(t *testing.T) {
 	require.NoError(t, err)
 
 	// Unmarshall and verify
-	unmarshalledCfg := &oauth2clientauthextension.Config{}
+	unmarshalledCfg := &oauth2clientextension.Config{}
 	err = conf.Unmarshal(unmarshalledCfg)
 	require.NoError(t, err)
 
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/pprofextension"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confignet"
+)
+
+const (
+	pprofName = "pprof"
+
+	// DefaultPprofEndpoint is the default listen address of the pprof extension. It is only reachable locally.
+	DefaultPprofEndpoint = "localhost:1777"
+)
+
+// PprofID is the component id of the pprof extension.
+var PprofID component.ID = component.NewID(pprofName)
+
+// PprofCfg generates the config for a pprof extension listening on the provided endpoint.
+func PprofCfg(endpoint string) *pprofextension.Config {
+	return &pprofextension.Config{
+		TCPAddr: confignet.TCPAddr{
+			Endpoint: endpoint,
+		},
+	}
+}
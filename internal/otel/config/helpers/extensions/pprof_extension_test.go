@@ -0,0 +1,30 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/pprofextension"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func Test_PprofExtension(t *testing.T) {
+	cfg := PprofCfg(DefaultPprofEndpoint)
+	require.NotNil(t, cfg)
+
+	// Marshall the configuration
+	conf := confmap.New()
+	err := conf.Marshal(cfg)
+	require.NoError(t, err)
+
+	// Unmarshall and verify
+	unmarshalledCfg := &pprofextension.Config{}
+	err = conf.Unmarshal(unmarshalledCfg)
+	require.NoError(t, err)
+
+	require.NoError(t, unmarshalledCfg.Validate())
+	require.Equal(t, cfg, unmarshalledCfg)
+}
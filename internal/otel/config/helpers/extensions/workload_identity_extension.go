@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/hashicorp/consul-telemetry-collector/extensions/workloadidentityextension"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/types"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+)
+
+const (
+	defaultAPIAddress = "api.cloud.hashicorp.com"
+
+	envVarAPIAddress = "HCP_API_ADDRESS"
+	envVarAPITLS     = "HCP_API_TLS"
+)
+
+// WorkloadIdentityID is the component.ID used by the workload identity extension.
+var WorkloadIdentityID component.ID = component.NewIDWithName(workloadidentityextension.ID, "hcp")
+
+// WorkloadIdentityIDFor returns the component.ID of the workload identity extension of the named HCP resource,
+// workload_identity/hcp-<name>.
+func WorkloadIdentityIDFor(name string) component.ID {
+	return component.NewIDWithName(workloadidentityextension.ID, "hcp-"+name)
+}
+
+// WorkloadIdentityConfig is a wrapper around the workloadidentityextension.Config which we cannot use directly
+// since its in memory PEM TLS settings get changed to REDACTED when marshalling.
+type WorkloadIdentityConfig struct {
+	// ProviderResourceName is the resource name of the HCP workload identity provider.
+	ProviderResourceName string `mapstructure:"provider_resource_name"`
+
+	// TokenFile is the file the workload identity token is read from.
+	TokenFile string `mapstructure:"token_file"`
+
+	// Endpoint is the URL of the HCP API the token is exchanged with.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// TLSSetting is the TLS configuration of the client connecting to the Endpoint.
+	TLSSetting types.TLSClientSetting `mapstructure:"tls,omitempty"`
+
+	// Proxy, when set, is the proxy the connections to the Endpoint go through.
+	Proxy *proxy.Config `mapstructure:"proxy,omitempty"`
+}
+
+// WorkloadIdentityCfg returns the workload identity extension config exchanging the token of the tokenFile with
+// the HCP API configured by the HCP_API_ADDRESS and HCP_API_TLS environment variables, like the HCP client. The
+// exchange goes through the proxy when it is enabled.
+func WorkloadIdentityCfg(providerResourceName, tokenFile string, p *proxy.Config) *WorkloadIdentityConfig {
+	address, ok := os.LookupEnv(envVarAPIAddress)
+	if !ok {
+		address = defaultAPIAddress
+	}
+
+	scheme := "https"
+	var tlsSetting types.TLSClientSetting
+	switch os.Getenv(envVarAPITLS) {
+	case tlsSettingDisabled:
+		scheme = "http"
+		tlsSetting.Insecure = true
+	case tlsSettingInsecure:
+		tlsSetting.InsecureSkipVerify = true
+	}
+
+	cfg := &WorkloadIdentityConfig{
+		ProviderResourceName: providerResourceName,
+		TokenFile:            tokenFile,
+		Endpoint:             fmt.Sprintf("%s://%s", scheme, address),
+		TLSSetting:           tlsSetting,
+	}
+	if p.Enabled() {
+		cfg.Proxy = p
+	}
+	return cfg
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/hashicorp/consul-telemetry-collector/extensions/workloadidentityextension"
+)
+
+func Test_WorkloadIdentity(t *testing.T) {
+	for name, tc := range map[string]struct {
+		env      map[string]string
+		endpoint string
+		insecure bool
+		skip     bool
+	}{
+		"Default": {
+			endpoint: "https://api.cloud.hashicorp.com",
+		},
+		"TLSDisabled": {
+			env:      map[string]string{"HCP_API_ADDRESS": "localhost:8080", "HCP_API_TLS": "disabled"},
+			endpoint: "http://localhost:8080",
+			insecure: true,
+		},
+		"TLSInsecure": {
+			env:      map[string]string{"HCP_API_ADDRESS": "localhost:8443", "HCP_API_TLS": "insecure"},
+			endpoint: "https://localhost:8443",
+			skip:     true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			cfg := WorkloadIdentityCfg("wip", "/var/run/token", nil)
+			require.Equal(t, tc.endpoint, cfg.Endpoint)
+			require.Equal(t, tc.insecure, cfg.TLSSetting.Insecure)
+			require.Equal(t, tc.skip, cfg.TLSSetting.InsecureSkipVerify)
+
+			// Marshall the configuration
+			conf := confmap.New()
+			require.NoError(t, conf.Marshal(cfg))
+
+			// Unmarshall into the extension's configuration and verify
+			extCfg := workloadidentityextension.CreateDefaultConfig().(*workloadidentityextension.Config)
+			require.NoError(t, conf.Unmarshal(extCfg))
+			require.NoError(t, extCfg.Validate())
+			require.Equal(t, "wip", extCfg.ProviderResourceName)
+			require.Equal(t, "/var/run/token", extCfg.TokenFile)
+			require.Equal(t, tc.endpoint, extCfg.Endpoint)
+		})
+	}
+}
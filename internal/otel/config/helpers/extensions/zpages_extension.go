@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/extension/zpagesextension"
+)
+
+const (
+	zpagesName = "zpages"
+
+	// DefaultZPagesEndpoint is the default listen address of the zpages extension. It is only reachable locally.
+	DefaultZPagesEndpoint = "localhost:55679"
+)
+
+// ZPagesID is the component id of the zpages extension.
+var ZPagesID component.ID = component.NewID(zpagesName)
+
+// ZPagesCfg generates the config for a zpages extension listening on the provided endpoint.
+func ZPagesCfg(endpoint string) *zpagesextension.Config {
+	return &zpagesextension.Config{
+		TCPAddr: confignet.TCPAddr{
+			Endpoint: endpoint,
+		},
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package extensions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/extension/zpagesextension"
+)
+
+func Test_ZPagesExtension(t *testing.T) {
+	cfg := ZPagesCfg(DefaultZPagesEndpoint)
+	require.NotNil(t, cfg)
+
+	// Marshall the configuration
+	conf := confmap.New()
+	err := conf.Marshal(cfg)
+	require.NoError(t, err)
+
+	// Unmarshall and verify
+	unmarshalledCfg := &zpagesextension.Config{}
+	err = conf.Unmarshal(unmarshalledCfg)
+	require.NoError(t, err)
+
+	require.NoError(t, unmarshalledCfg.Validate())
+	require.Equal(t, cfg, unmarshalledCfg)
+}
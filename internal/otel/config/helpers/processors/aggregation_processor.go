@@ -0,0 +1,36 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+)
+
+// AggregationHCPID is the component id of the aggregation processor of the HCP pipeline.
+var AggregationHCPID component.ID = component.NewIDWithName(aggregationprocessor.ID, "hcp")
+
+// AggregationExternalID is the component id of the aggregation processor of the external pipeline.
+var AggregationExternalID component.ID = component.NewIDWithName(aggregationprocessor.ID, "external")
+
+// AggregationCfg generates the config for an aggregation processor. The resource attributes, interval, staleness
+// and gauge function default to the processor defaults when they are not set in the aggregation.
+func AggregationCfg(aggregation aggregationprocessor.Config) *aggregationprocessor.Config {
+	cfg := aggregationprocessor.CreateDefaultConfig().(*aggregationprocessor.Config)
+	if len(aggregation.DropResourceAttributes) > 0 {
+		cfg.DropResourceAttributes = aggregation.DropResourceAttributes
+	}
+	if aggregation.Interval != 0 {
+		cfg.Interval = aggregation.Interval
+	}
+	if aggregation.MaxStaleness != 0 {
+		cfg.MaxStaleness = aggregation.MaxStaleness
+	}
+	if aggregation.GaugeFunction != "" {
+		cfg.GaugeFunction = aggregation.GaugeFunction
+	}
+
+	return cfg
+}
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+)
+
+func Test_Aggregation(t *testing.T) {
+	for name, tc := range map[string]struct {
+		aggregation aggregationprocessor.Config
+		expect      aggregationprocessor.Config
+	}{
+		"Defaults": {
+			expect: aggregationprocessor.Config{
+				DropResourceAttributes: []string{"node.id"},
+				Interval:               time.Minute,
+				MaxStaleness:           5 * time.Minute,
+				GaugeFunction:          "sum",
+			},
+		},
+		"AllSet": {
+			aggregation: aggregationprocessor.Config{
+				DropResourceAttributes: []string{"node.id", "namespace"},
+				Interval:               30 * time.Second,
+				MaxStaleness:           time.Minute,
+				GaugeFunction:          "max",
+			},
+			expect: aggregationprocessor.Config{
+				DropResourceAttributes: []string{"node.id", "namespace"},
+				Interval:               30 * time.Second,
+				MaxStaleness:           time.Minute,
+				GaugeFunction:          "max",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := AggregationCfg(tc.aggregation)
+			require.Equal(t, &tc.expect, cfg)
+			require.NoError(t, cfg.Validate())
+
+			// Marshall the configuration
+			conf := confmap.New()
+			err := conf.Marshal(cfg)
+			require.NoError(t, err)
+
+			// Unmarshall and verify
+			unmarshalledCfg := &aggregationprocessor.Config{}
+			err = conf.Unmarshal(unmarshalledCfg)
+			require.NoError(t, err)
+
+			require.Equal(t, cfg, unmarshalledCfg)
+		})
+	}
+}
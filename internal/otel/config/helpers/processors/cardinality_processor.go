@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+)
+
+// CardinalityLimiterID is the component id of the cardinality limiter processor.
+var CardinalityLimiterID component.ID = component.NewID(cardinalityprocessor.ID)
+
+// CardinalityLimiterCfg generates the config for a cardinality limiter processor. The window and overflow action
+// default to the processor defaults when they are not set in the limits.
+func CardinalityLimiterCfg(limits cardinalityprocessor.Config) *cardinalityprocessor.Config {
+	cfg := cardinalityprocessor.CreateDefaultConfig().(*cardinalityprocessor.Config)
+	cfg.MaxSeriesPerMetric = limits.MaxSeriesPerMetric
+	cfg.MaxSeriesPerNode = limits.MaxSeriesPerNode
+	if limits.Window != 0 {
+		cfg.Window = limits.Window
+	}
+	if limits.OverflowAction != "" {
+		cfg.OverflowAction = limits.OverflowAction
+	}
+
+	return cfg
+}
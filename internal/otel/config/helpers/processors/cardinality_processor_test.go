@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+)
+
+func Test_CardinalityLimiter(t *testing.T) {
+	for name, tc := range map[string]struct {
+		limits cardinalityprocessor.Config
+		expect cardinalityprocessor.Config
+	}{
+		"Defaults": {
+			limits: cardinalityprocessor.Config{MaxSeriesPerMetric: 10},
+			expect: cardinalityprocessor.Config{
+				MaxSeriesPerMetric: 10,
+				Window:             10 * time.Minute,
+				OverflowAction:     cardinalityprocessor.OverflowActionAggregate,
+			},
+		},
+		"AllSet": {
+			limits: cardinalityprocessor.Config{
+				MaxSeriesPerMetric: 10,
+				MaxSeriesPerNode:   100,
+				Window:             time.Minute,
+				OverflowAction:     cardinalityprocessor.OverflowActionDrop,
+			},
+			expect: cardinalityprocessor.Config{
+				MaxSeriesPerMetric: 10,
+				MaxSeriesPerNode:   100,
+				Window:             time.Minute,
+				OverflowAction:     cardinalityprocessor.OverflowActionDrop,
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := CardinalityLimiterCfg(tc.limits)
+			require.Equal(t, &tc.expect, cfg)
+			require.NoError(t, cfg.Validate())
+
+			// Marshall the configuration
+			conf := confmap.New()
+			err := conf.Marshal(cfg)
+			require.NoError(t, err)
+
+			// Unmarshall and verify
+			unmarshalledCfg := &cardinalityprocessor.Config{}
+			err = conf.Unmarshal(unmarshalledCfg)
+			require.NoError(t, err)
+
+			require.Equal(t, cfg, unmarshalledCfg)
+		})
+	}
+}
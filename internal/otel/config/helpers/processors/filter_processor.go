@@ -18,6 +18,11 @@ const filterProcessorName = "filter"
 // FilterProcessorID is the component id of the filter processor.
 var FilterProcessorID component.ID = component.NewID(filterProcessorName)
 
+// FilterProcessorIDFor returns the component id of the filter processor of the named HCP resource, filter/hcp-<name>.
+func FilterProcessorIDFor(name string) component.ID {
+	return component.NewIDWithName(filterProcessorName, "hcp-"+name)
+}
+
 const (
 	regexpMatchType = "regexp"
 )
@@ -38,17 +43,11 @@ type MatchProperties struct {
 	MetricNames []string `mapstructure:"metric_names"`
 }
 
-// FilterProcessorCfg generates the config for a filter processor.
-func FilterProcessorCfg(client hcp.TelemetryClient) *FilterProcessorConfig {
+// FilterProcessorCfg generates the config for a filter processor from the HCP include list.
+func FilterProcessorCfg(telemetryCfg hcp.TelemetryConfig) *FilterProcessorConfig {
 	usableFilters := []string{}
-	filters, err := client.MetricFilters()
 	logger := hclog.Default().Named("config/helpers")
-	if err != nil {
-		// log failure here, but it's not fatal because the gateway should also filter metrics
-		logger.Warn("failed to retrieve metric filters from HCP", "error", err)
-		return &FilterProcessorConfig{}
-	}
-	for _, filter := range filters {
+	for _, filter := range telemetryCfg.IncludeList {
 		if err := validateFilter(filter); err != nil {
 			// log failure here, but it's not fatal because the gateway should also filter metrics
 			logger.Warn("failed to validate filter", "filter", filter, "error", err)
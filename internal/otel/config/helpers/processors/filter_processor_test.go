@@ -4,7 +4,6 @@
 package processors
 
 import (
-	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -15,24 +14,26 @@ import (
 
 func Test_FilterProcessor(t *testing.T) {
 	testcases := map[string]struct {
-		mockErr error
+		empty bool
 	}{
 		"Success": {},
-		"FilterGetError": {
-			mockErr: errors.New("boom"),
+		"NoFilters": {
+			empty: true,
 		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
-			clientM := &hcp.MockClient{
-				MockMetricFilters: []string{
+			telemetryCfg := hcp.TelemetryConfig{
+				IncludeList: []string{
 					"^consul.consul.envoy.connection$",
 					"^envoy.*connection$",
 					"[a-z",
 				},
-				Err: tc.mockErr,
 			}
-			cfg := FilterProcessorCfg(clientM)
+			if tc.empty {
+				telemetryCfg = hcp.TelemetryConfig{}
+			}
+			cfg := FilterProcessorCfg(telemetryCfg)
 			require.NotNil(t, cfg)
 
 			// Marshall the configuration
@@ -44,7 +45,7 @@ func Test_FilterProcessor(t *testing.T) {
 			unmarshalledCfg := &FilterProcessorConfig{}
 			err = conf.Unmarshal(&unmarshalledCfg)
 			require.NoError(t, err)
-			if tc.mockErr == nil {
+			if !tc.empty {
 				require.Equal(t, []string{"^consul.consul.envoy.connection$", "^envoy.*connection$"},
 					unmarshalledCfg.Metrics.Include.MetricNames)
 			}
@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// LocalFilterID is the component id of the filter processor applying the user's filter. It is distinct from
+// the FilterProcessorID applying the HCP filters so both can be layered in the HCP pipeline.
+var LocalFilterID component.ID = component.NewIDWithName(filterProcessorName, "local")
+
+// ignoreErrorMode logs the errors evaluating a condition instead of dropping the whole payload.
+const ignoreErrorMode = "ignore"
+
+// LocalFilterConfig configures a filter processor with OTTL conditions. A metric or data point is dropped when
+// any condition is true.
+type LocalFilterConfig struct {
+	ErrorMode string             `mapstructure:"error_mode"`
+	Metrics   LocalMetricFilters `mapstructure:"metrics"`
+}
+
+// LocalMetricFilters are the OTTL conditions dropping metrics and data points.
+type LocalMetricFilters struct {
+	MetricConditions    []string `mapstructure:"metric,omitempty"`
+	DataPointConditions []string `mapstructure:"datapoint,omitempty"`
+}
+
+// LocalFilter is the user's allow and deny list of metrics. Every value is a regular expression.
+type LocalFilter struct {
+	// IncludeMetrics keeps only the metrics whose name matches one of the regexes when it is not empty.
+	IncludeMetrics []string
+	// ExcludeMetrics drops the metrics whose name matches one of the regexes.
+	ExcludeMetrics []string
+	// IncludeAttributes keeps only the data points whose attribute, or resource attribute, matches the regex of
+	// every key.
+	IncludeAttributes map[string]string
+	// ExcludeAttributes drops the data points whose attribute, or resource attribute, matches the regex of any key.
+	ExcludeAttributes map[string]string
+}
+
+// Validate checks that every regex of the filter compiles.
+func (f LocalFilter) Validate() error {
+	for _, filters := range [][]string{f.IncludeMetrics, f.ExcludeMetrics} {
+		for _, filter := range filters {
+			if err := validateFilter(filter); err != nil {
+				return fmt.Errorf("invalid metric filter %q: %w", filter, err)
+			}
+		}
+	}
+	for _, filters := range []map[string]string{f.IncludeAttributes, f.ExcludeAttributes} {
+		for key, filter := range filters {
+			if err := validateFilter(filter); err != nil {
+				return fmt.Errorf("invalid filter %q of attribute %q: %w", filter, key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LocalFilterCfg generates the config for the filter processor applying the filter.
+func LocalFilterCfg(filter LocalFilter) *LocalFilterConfig {
+	cfg := &LocalFilterConfig{
+		ErrorMode: ignoreErrorMode,
+	}
+
+	if len(filter.IncludeMetrics) > 0 {
+		cfg.Metrics.MetricConditions = append(cfg.Metrics.MetricConditions,
+			fmt.Sprintf("not (%s)", anyMatch("name", filter.IncludeMetrics)))
+	}
+	for _, exclude := range filter.ExcludeMetrics {
+		cfg.Metrics.MetricConditions = append(cfg.Metrics.MetricConditions, isMatch("name", exclude))
+	}
+
+	for _, key := range sortedKeys(filter.IncludeAttributes) {
+		cfg.Metrics.DataPointConditions = append(cfg.Metrics.DataPointConditions,
+			fmt.Sprintf("not (%s)", attributeMatch(key, filter.IncludeAttributes[key])))
+	}
+	for _, key := range sortedKeys(filter.ExcludeAttributes) {
+		cfg.Metrics.DataPointConditions = append(cfg.Metrics.DataPointConditions,
+			attributeMatch(key, filter.ExcludeAttributes[key]))
+	}
+
+	return cfg
+}
+
+// attributeMatch is the OTTL condition matching the data points whose attribute, or resource attribute, key
+// matches the pattern.
+func attributeMatch(key, pattern string) string {
+	quoted := strconv.Quote(key)
+	return anyMatch(fmt.Sprintf("attributes[%s]", quoted), []string{pattern}) + " or " +
+		anyMatch(fmt.Sprintf("resource.attributes[%s]", quoted), []string{pattern})
+}
+
+// anyMatch is the OTTL condition matching when the path matches any of the patterns.
+func anyMatch(path string, patterns []string) string {
+	conditions := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		conditions = append(conditions, isMatch(path, pattern))
+	}
+	return strings.Join(conditions, " or ")
+}
+
+func isMatch(path, pattern string) string {
+	return fmt.Sprintf("IsMatch(%s, %s) == true", path, strconv.Quote(pattern))
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+func Test_LocalFilter(t *testing.T) {
+	filter := LocalFilter{
+		IncludeMetrics: []string{`^cluster\.`, `^http\.`},
+		ExcludeMetrics: []string{`_bucket$`},
+		IncludeAttributes: map[string]string{
+			"partition": "^team-a$",
+		},
+		ExcludeAttributes: map[string]string{
+			"envoy.cluster_name": `^internal-`,
+		},
+	}
+	require.NoError(t, filter.Validate())
+
+	cfg := LocalFilterCfg(filter)
+	require.Equal(t, &LocalFilterConfig{
+		ErrorMode: "ignore",
+		Metrics: LocalMetricFilters{
+			MetricConditions: []string{
+				`not (IsMatch(name, "^cluster\\.") == true or IsMatch(name, "^http\\.") == true)`,
+				`IsMatch(name, "_bucket$") == true`,
+			},
+			DataPointConditions: []string{
+				`not (IsMatch(attributes["partition"], "^team-a$") == true or ` +
+					`IsMatch(resource.attributes["partition"], "^team-a$") == true)`,
+				`IsMatch(attributes["envoy.cluster_name"], "^internal-") == true or ` +
+					`IsMatch(resource.attributes["envoy.cluster_name"], "^internal-") == true`,
+			},
+		},
+	}, cfg)
+
+	// Marshall the configuration
+	conf := confmap.New()
+	err := conf.Marshal(cfg)
+	require.NoError(t, err)
+
+	// Unmarshall and verify
+	unmarshalledCfg := &LocalFilterConfig{}
+	err = conf.Unmarshal(unmarshalledCfg)
+	require.NoError(t, err)
+	require.Equal(t, cfg, unmarshalledCfg)
+
+	// the conditions must be valid for the filter processor.
+	processorCfg := filterprocessor.NewFactory().CreateDefaultConfig().(*filterprocessor.Config)
+	require.NoError(t, conf.Unmarshal(processorCfg))
+	require.NoError(t, processorCfg.Validate())
+
+	sink := new(consumertest.MetricsSink)
+	p, err := filterprocessor.NewFactory().CreateMetricsProcessor(context.Background(),
+		processortest.NewNopCreateSettings(), processorCfg, sink)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("partition", "team-a")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+	for _, name := range []string{"cluster.upstream_rq", "cluster.upstream_rq_bucket", "server.uptime"} {
+		m := metrics.AppendEmpty()
+		m.SetName(name)
+		gauge := m.SetEmptyGauge()
+		for _, cluster := range []string{"api", "internal-admin"} {
+			dp := gauge.DataPoints().AppendEmpty()
+			dp.Attributes().PutStr("envoy.cluster_name", cluster)
+		}
+	}
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	filtered := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 1, filtered.Len())
+	require.Equal(t, "cluster.upstream_rq", filtered.At(0).Name())
+	require.Equal(t, 1, filtered.At(0).Gauge().DataPoints().Len())
+}
+
+func Test_LocalFilterInvalid(t *testing.T) {
+	require.ErrorContains(t, LocalFilter{ExcludeMetrics: []string{"[a-z"}}.Validate(), "[a-z")
+	require.ErrorContains(t, LocalFilter{
+		IncludeAttributes: map[string]string{"namespace": "(default"},
+	}.Validate(), "namespace")
+}
@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const metricsTransformProcessorName = "metricstransform"
+
+// MetricsTransformID is the component id of the metrics transform processor.
+var MetricsTransformID component.ID = component.NewID(metricsTransformProcessorName)
+
+const (
+	strictMatchType   = "strict"
+	updateAction      = "update"
+	updateLabelAction = "update_label"
+)
+
+// MetricsTransformConfig configures the metrics transform processor.
+type MetricsTransformConfig struct {
+	Transforms []Transform `mapstructure:"transforms"`
+}
+
+// Transform updates the metrics whose name matches Include.
+type Transform struct {
+	Include    string      `mapstructure:"include"`
+	MatchType  string      `mapstructure:"match_type"`
+	Action     string      `mapstructure:"action"`
+	NewName    string      `mapstructure:"new_name,omitempty"`
+	Operations []Operation `mapstructure:"operations,omitempty"`
+}
+
+// Operation renames the Label attribute of the data points to NewLabel.
+type Operation struct {
+	Action   string `mapstructure:"action"`
+	Label    string `mapstructure:"label"`
+	NewLabel string `mapstructure:"new_label"`
+}
+
+// Renames maps envoy metric and attribute names to new names.
+type Renames struct {
+	// Metrics maps envoy metric names to their new names.
+	Metrics map[string]string
+	// Attributes maps envoy data point attribute names to their new names on every metric.
+	Attributes map[string]string
+}
+
+// defaultMetricRenames map envoy metrics to their OpenTelemetry semantic convention names.
+// See https://opentelemetry.io/docs/specs/semconv/http/http-metrics/
+var defaultMetricRenames = map[string]string{
+	"http.downstream_rq_time":       "http.server.request.duration",
+	"http.downstream_rq_active":     "http.server.active_requests",
+	"cluster.upstream_rq_time":      "http.client.request.duration",
+	"cluster.upstream_rq_active":    "http.client.active_requests",
+	"cluster.upstream_cx_active":    "http.client.open_connections",
+	"cluster.upstream_cx_length_ms": "http.client.connection.duration",
+	// the name of cluster.upstream_cx_length_ms when unit suffixes are stripped.
+	"cluster.upstream_cx_length": "http.client.connection.duration",
+}
+
+// defaultAttributeRenames map the attributes extracted by envoy to their OpenTelemetry semantic convention names.
+var defaultAttributeRenames = map[string]string{
+	"envoy.response_code": "http.response.status_code",
+}
+
+// DefaultRenames returns the mapping of envoy metric and attribute names to OpenTelemetry semantic conventions.
+func DefaultRenames() Renames {
+	r := Renames{
+		Metrics:    make(map[string]string, len(defaultMetricRenames)),
+		Attributes: make(map[string]string, len(defaultAttributeRenames)),
+	}
+	for k, v := range defaultMetricRenames {
+		r.Metrics[k] = v
+	}
+	for k, v := range defaultAttributeRenames {
+		r.Attributes[k] = v
+	}
+	return r
+}
+
+// MetricsTransformCfg generates the config for a metrics transform processor applying the renames. Metrics are
+// renamed first, then the attributes of every metric.
+func MetricsTransformCfg(renames Renames) *MetricsTransformConfig {
+	cfg := &MetricsTransformConfig{
+		Transforms: []Transform{},
+	}
+
+	for _, name := range sortedKeys(renames.Metrics) {
+		cfg.Transforms = append(cfg.Transforms, Transform{
+			Include:   name,
+			MatchType: strictMatchType,
+			Action:    updateAction,
+			NewName:   renames.Metrics[name],
+		})
+	}
+
+	if len(renames.Attributes) == 0 {
+		return cfg
+	}
+
+	operations := make([]Operation, 0, len(renames.Attributes))
+	for _, attr := range sortedKeys(renames.Attributes) {
+		operations = append(operations, Operation{
+			Action:   updateLabelAction,
+			Label:    attr,
+			NewLabel: renames.Attributes[attr],
+		})
+	}
+	cfg.Transforms = append(cfg.Transforms, Transform{
+		Include:    ".*",
+		MatchType:  regexpMatchType,
+		Action:     updateAction,
+		Operations: operations,
+	})
+
+	return cfg
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
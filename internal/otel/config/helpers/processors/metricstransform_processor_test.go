@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+)
+
+func Test_MetricsTransform(t *testing.T) {
+	cfg := MetricsTransformCfg(Renames{
+		Metrics: map[string]string{
+			"http.downstream_rq_time":  "http.server.request.duration",
+			"cluster.upstream_rq_time": "http.client.request.duration",
+		},
+		Attributes: map[string]string{
+			"envoy.response_code": "http.response.status_code",
+		},
+	})
+
+	require.Equal(t, &MetricsTransformConfig{
+		Transforms: []Transform{
+			{
+				Include:   "cluster.upstream_rq_time",
+				MatchType: "strict",
+				Action:    "update",
+				NewName:   "http.client.request.duration",
+			},
+			{
+				Include:   "http.downstream_rq_time",
+				MatchType: "strict",
+				Action:    "update",
+				NewName:   "http.server.request.duration",
+			},
+			{
+				Include:   ".*",
+				MatchType: "regexp",
+				Action:    "update",
+				Operations: []Operation{
+					{
+						Action:   "update_label",
+						Label:    "envoy.response_code",
+						NewLabel: "http.response.status_code",
+					},
+				},
+			},
+		},
+	}, cfg)
+
+	// Marshall the configuration
+	conf := confmap.New()
+	err := conf.Marshal(cfg)
+	require.NoError(t, err)
+
+	// Unmarshall and verify
+	unmarshalledCfg := &MetricsTransformConfig{}
+	err = conf.Unmarshal(unmarshalledCfg)
+	require.NoError(t, err)
+
+	require.Equal(t, cfg, unmarshalledCfg)
+}
+
+func Test_DefaultRenames(t *testing.T) {
+	renames := DefaultRenames()
+	require.Equal(t, "http.server.request.duration", renames.Metrics["http.downstream_rq_time"])
+	require.Equal(t, "http.response.status_code", renames.Attributes["envoy.response_code"])
+
+	// the defaults are copied so they can be overridden.
+	renames.Metrics["http.downstream_rq_time"] = "renamed"
+	require.Equal(t, "http.server.request.duration", DefaultRenames().Metrics["http.downstream_rq_time"])
+}
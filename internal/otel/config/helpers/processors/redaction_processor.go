@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+
+	"github.com/hashicorp/consul-telemetry-collector/processors/redactionprocessor"
+)
+
+// RedactionHCPID is the component id of the redaction processor of the HCP pipeline.
+var RedactionHCPID component.ID = component.NewIDWithName(redactionprocessor.ID, "hcp")
+
+// RedactionExternalID is the component id of the redaction processor of the external pipeline.
+var RedactionExternalID component.ID = component.NewIDWithName(redactionprocessor.ID, "external")
+
+// RedactionRouteIDFor returns the component id of the redaction processor of the named route, redaction/route-<name>.
+func RedactionRouteIDFor(name string) component.ID {
+	return component.NewIDWithName(redactionprocessor.ID, "route-"+name)
+}
+
+// RedactionConfig is a wrapper around the redactionprocessor.Config. The hash key of the processor is a
+// configopaque.String, which marshals to [REDACTED], so it is rendered as a plain string.
+type RedactionConfig struct {
+	ResourceAttributes []redactionprocessor.Redaction `mapstructure:"resource_attributes,omitempty"`
+	Attributes         []redactionprocessor.Redaction `mapstructure:"attributes,omitempty"`
+	HashKey            string                         `mapstructure:"hash_key,omitempty"`
+}
+
+// DefaultHCPRedaction is the redaction of the HCP pipeline when none is configured. The node.id of a proxy
+// embeds its pod or host name, so it is hashed with the key to keep the proxies apart without sending their names
+// to HCP.
+func DefaultHCPRedaction(hashKey string) redactionprocessor.Config {
+	return redactionprocessor.Config{
+		ResourceAttributes: []redactionprocessor.Redaction{
+			{Key: "node.id", Action: redactionprocessor.ActionHash},
+		},
+		HashKey: configopaque.String(hashKey),
+	}
+}
+
+// RedactionCfg generates the config for a redaction processor.
+func RedactionCfg(redaction redactionprocessor.Config) *RedactionConfig {
+	return &RedactionConfig{
+		ResourceAttributes: redaction.ResourceAttributes,
+		Attributes:         redaction.Attributes,
+		HashKey:            string(redaction.HashKey),
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package processors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/hashicorp/consul-telemetry-collector/processors/redactionprocessor"
+)
+
+func Test_Redaction(t *testing.T) {
+	for name, tc := range map[string]struct {
+		redaction redactionprocessor.Config
+	}{
+		"DefaultHCP": {
+			redaction: DefaultHCPRedaction("key"),
+		},
+		"AllSet": {
+			redaction: redactionprocessor.Config{
+				ResourceAttributes: []redactionprocessor.Redaction{
+					{Key: "node.id", Action: redactionprocessor.ActionHash},
+				},
+				Attributes: []redactionprocessor.Redaction{
+					{Key: "pod", Action: redactionprocessor.ActionDrop},
+					{Key: "tenant", Action: redactionprocessor.ActionMask},
+				},
+				HashKey: "key",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := RedactionCfg(tc.redaction)
+
+			// Marshall the configuration
+			conf := confmap.New()
+			err := conf.Marshal(cfg)
+			require.NoError(t, err)
+
+			// Unmarshall into the processor's configuration and verify the key is not redacted
+			unmarshalledCfg := &redactionprocessor.Config{}
+			err = conf.Unmarshal(unmarshalledCfg)
+			require.NoError(t, err)
+			require.NoError(t, unmarshalledCfg.Validate())
+
+			require.Equal(t, &tc.redaction, unmarshalledCfg)
+		})
+	}
+}
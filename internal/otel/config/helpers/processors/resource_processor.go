@@ -7,7 +7,6 @@ import (
 	"go.opentelemetry.io/collector/component"
 
 	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
-	"github.com/hashicorp/go-hclog"
 )
 
 const resourceProcessorName = "resource"
@@ -15,6 +14,12 @@ const resourceProcessorName = "resource"
 // ResourceProcessorID is the component id of the resource processor.
 var ResourceProcessorID component.ID = component.NewID(resourceProcessorName)
 
+// ResourceProcessorIDFor returns the component id of the resource processor of the named HCP resource,
+// resource/hcp-<name>.
+func ResourceProcessorIDFor(name string) component.ID {
+	return component.NewIDWithName(resourceProcessorName, "hcp-"+name)
+}
+
 // ResourceProcessorConfig configures the Resource Processor.
 type ResourceProcessorConfig struct {
 	Attributes []Actions `mapstructure:"attributes"`
@@ -55,17 +60,9 @@ type Actions struct {
 
 // ResourcesProcessorCfg generates the config for a resource processor.
 // The cluster's ResourceID is upserted as a label in all metrics.
-func ResourcesProcessorCfg(client hcp.TelemetryClient) ResourceProcessorConfig {
-	logger := hclog.Default().Named("config/helpers")
-
-	metricAttributes, err := client.MetricAttributes()
-	if err != nil {
-		logger.Error("failed to retrieve metric labels from HCP", "error", err)
-		return ResourceProcessorConfig{}
-	}
-
-	actions := make([]Actions, 0, len(metricAttributes))
-	for key, value := range metricAttributes {
+func ResourcesProcessorCfg(telemetryCfg hcp.TelemetryConfig) ResourceProcessorConfig {
+	actions := make([]Actions, 0, len(telemetryCfg.Labels))
+	for key, value := range telemetryCfg.Labels {
 		actions = append(actions, Actions{
 			Key:    key,
 			Value:  value,
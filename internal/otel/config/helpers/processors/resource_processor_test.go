@@ -4,7 +4,6 @@
 package processors
 
 import (
-	"errors"
 	"testing"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourceprocessor"
@@ -16,23 +15,25 @@ import (
 
 func Test_ResourceProcessorCfg(t *testing.T) {
 	for name, tc := range map[string]struct {
-		mockErr error
+		empty bool
 	}{
 		"Success": {},
-		"GetError": {
-			mockErr: errors.New("boom"),
+		"NoLabels": {
+			empty: true,
 		},
 	} {
 		t.Run(name, func(t *testing.T) {
-			clientM := &hcp.MockClient{
-				MockMetricAttributes: map[string]string{
+			telemetryCfg := hcp.TelemetryConfig{
+				Labels: map[string]string{
 					"cluster": "name",
 					"org":     "fake-org",
 				},
-				Err: tc.mockErr,
+			}
+			if tc.empty {
+				telemetryCfg = hcp.TelemetryConfig{}
 			}
 
-			cfg := ResourcesProcessorCfg(clientM)
+			cfg := ResourcesProcessorCfg(telemetryCfg)
 			require.NotNil(t, cfg)
 
 			// Marshal the configuration
@@ -45,7 +46,7 @@ func Test_ResourceProcessorCfg(t *testing.T) {
 			err = conf.Unmarshal(unmarshalledCfg)
 			require.NoError(t, err)
 
-			if tc.mockErr != nil {
+			if tc.empty {
 				require.Empty(t, unmarshalledCfg.AttributesActions)
 				return
 			}
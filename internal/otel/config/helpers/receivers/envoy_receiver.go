@@ -6,6 +6,7 @@ package receivers
 
 import (
 	"fmt"
+	"maps"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
@@ -21,10 +22,27 @@ type Config struct {
 	GRPC *configgrpc.GRPCServerSettings `mapstructure:"grpc,omitempty"`
 }
 
-// EnvoyReceiverCfg  generates the config for an otlp receiver.
-func EnvoyReceiverCfg(listenerPort int) *envoyreceiver.Config {
+// EnvoyReceiverCfg  generates the config for an otlp receiver. Tag extraction from envoy stat names is enabled
+// when tagExtraction is set and stripUnitSuffixes removes the unit suffixes from the metric names. The
+// resourceAttributes, when set, are node properties set as resource attributes in addition to the default namespace
+// and partition node metadata. Their node metadata is merged with the defaults, which can be renamed but not removed,
+// like the collector merges the maps of a configuration with the defaults of the receiver.
+func EnvoyReceiverCfg(
+	listenerPort int,
+	tagExtraction *envoyreceiver.TagExtractionConfig,
+	stripUnitSuffixes bool,
+	resourceAttributes *envoyreceiver.ResourceAttributesConfig,
+) *envoyreceiver.Config {
 	defaults := envoyreceiver.NewFactory().CreateDefaultConfig().(*envoyreceiver.Config)
 	defaults.GRPC.NetAddr.Endpoint = fmt.Sprintf("127.0.0.1:%d", listenerPort)
+	defaults.TagExtraction = tagExtraction
+	defaults.StripUnitSuffixes = stripUnitSuffixes
+	if resourceAttributes != nil {
+		nodeMetadata := defaults.ResourceAttributes.NodeMetadata
+		defaults.ResourceAttributes = *resourceAttributes
+		defaults.ResourceAttributes.NodeMetadata = nodeMetadata
+		maps.Copy(defaults.ResourceAttributes.NodeMetadata, resourceAttributes.NodeMetadata)
+	}
 
 	return defaults
 }
@@ -13,7 +13,58 @@ import (
 )
 
 func Test_EnvoyReceiver(t *testing.T) {
-	cfg := EnvoyReceiverCfg(0)
+	cfg := EnvoyReceiverCfg(0, nil, false, nil)
+
+	conf := confmap.New()
+	err := conf.Marshal(cfg)
+	require.NoError(t, err)
+
+	// Unmarshall and verify
+	unmarshalledCfg := &envoyreceiver.Config{}
+	err = conf.Unmarshal(unmarshalledCfg)
+	require.NoError(t, err)
+
+	require.Equal(t, cfg, unmarshalledCfg)
+}
+
+func Test_EnvoyReceiverTagExtraction(t *testing.T) {
+	cfg := EnvoyReceiverCfg(0, &envoyreceiver.TagExtractionConfig{
+		DisableDefaults: true,
+		Extractors: []envoyreceiver.TagExtractorConfig{
+			{
+				Name:  "envoy.route",
+				Regex: `^vhost\.(?:.*?\.)?route\.((.+?)\.)`,
+			},
+		},
+	}, true, nil)
+
+	conf := confmap.New()
+	err := conf.Marshal(cfg)
+	require.NoError(t, err)
+
+	// Unmarshall and verify
+	unmarshalledCfg := &envoyreceiver.Config{}
+	err = conf.Unmarshal(unmarshalledCfg)
+	require.NoError(t, err)
+
+	require.Equal(t, cfg, unmarshalledCfg)
+}
+
+func Test_EnvoyReceiverResourceAttributes(t *testing.T) {
+	cfg := EnvoyReceiverCfg(0, nil, false, &envoyreceiver.ResourceAttributesConfig{
+		NodeMetadata: map[string]string{
+			"datacenter": "consul.datacenter",
+		},
+		Locality: map[string]string{
+			"region": "cloud.region",
+		},
+		BuildVersion: "envoy.version",
+	})
+	require.Equal(t, map[string]string{
+		"namespace":  "namespace",
+		"partition":  "partition",
+		"datacenter": "consul.datacenter",
+	}, cfg.ResourceAttributes.NodeMetadata)
 
 	conf := confmap.New()
 	err := conf.Marshal(cfg)
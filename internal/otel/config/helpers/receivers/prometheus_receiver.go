@@ -5,8 +5,9 @@
 package receivers
 
 import (
-	"fmt"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"go.opentelemetry.io/collector/component"
 )
 
@@ -35,7 +36,8 @@ type StaticConfig struct {
 }
 
 // PrometheusReceiverCfg  generates the prometheus config for scraping the local telemetry-collector metrics
-func PrometheusReceiverCfg(metricsPort int) *PrometheusConfig {
+// served on the metricsTarget host:port every scrapeInterval.
+func PrometheusReceiverCfg(metricsTarget string, scrapeInterval time.Duration) *PrometheusConfig {
 	// This should create a config that looks like this for scraping our own metrics
 	/*
 		prometheus:
@@ -53,10 +55,10 @@ func PrometheusReceiverCfg(metricsPort int) *PrometheusConfig {
 			scrapeConfigKey: {
 				{
 					JobName:        "consul-telemetry-collector",
-					ScrapeInterval: "1m",
+					ScrapeInterval: model.Duration(scrapeInterval).String(),
 					StaticConfigs: []StaticConfig{
 						{
-							Targets: []string{fmt.Sprintf("localhost:%d", metricsPort)},
+							Targets: []string{metricsTarget},
 						},
 					},
 				},
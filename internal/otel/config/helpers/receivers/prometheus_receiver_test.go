@@ -6,6 +6,7 @@ package receivers
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusreceiver"
 	"github.com/stretchr/testify/require"
@@ -13,7 +14,7 @@ import (
 )
 
 func Test_PrometheusReceiverCfg(t *testing.T) {
-	cfg := PrometheusReceiverCfg(0)
+	cfg := PrometheusReceiverCfg("localhost:9090", 30*time.Second)
 
 	conf := confmap.New()
 	err := conf.Marshal(cfg)
@@ -29,4 +30,5 @@ func Test_PrometheusReceiverCfg(t *testing.T) {
 	require.NoError(t, unmarshalledCfg.Validate())
 	require.NotNil(t, unmarshalledCfg.PrometheusConfig)
 	require.Len(t, unmarshalledCfg.PrometheusConfig.ScrapeConfigs, 1)
+	require.Equal(t, "30s", unmarshalledCfg.PrometheusConfig.ScrapeConfigs[0].ScrapeInterval.String())
 }
@@ -4,7 +4,7 @@
 package config
 
 import (
-	"fmt"
+	"strings"
 
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/service/telemetry"
@@ -16,18 +16,41 @@ const ( // supported trace propagators
 	b3Propagator           = "b3"
 )
 
-// Telemetry returns our basic telemetry configuration.
-func Telemetry(metricsPort int) telemetry.Config {
+const ( // supported log encodings
+	consoleEncoding = "console"
+	jsonEncoding    = "json"
+)
+
+// TelemetryParams are the inputs to the collector's own telemetry configuration.
+type TelemetryParams struct {
+	// MetricsAddress is the host:port the internal metrics are served on.
+	MetricsAddress string
+	// MetricsLevel is the configtelemetry level name of the internal metrics. Defaults to detailed.
+	MetricsLevel string
+	// LogLevel is the hclog level name the collector logs at. Defaults to info.
+	LogLevel string
+	// LogJSON sets the log encoding to JSON.
+	LogJSON bool
+}
+
+// Telemetry returns our basic telemetry configuration. The collector logs at the zap equivalent
+// of the hclog log level, in JSON when LogJSON is set.
+func Telemetry(p TelemetryParams) telemetry.Config {
+	encoding := consoleEncoding
+	if p.LogJSON {
+		encoding = jsonEncoding
+	}
+
 	return telemetry.Config{
 		Logs: telemetry.LogsConfig{
-			Level:            zapcore.InfoLevel,
-			Encoding:         "console",
+			Level:            LogLevel(p.LogLevel),
+			Encoding:         encoding,
 			OutputPaths:      []string{"stderr"},
 			ErrorOutputPaths: []string{"stderr"},
 		},
 		Metrics: telemetry.MetricsConfig{
-			Address: fmt.Sprintf("localhost:%d", metricsPort),
-			Level:   configtelemetry.LevelDetailed,
+			Address: p.MetricsAddress,
+			Level:   MetricsLevel(p.MetricsLevel),
 			Readers: []telemetry.MetricReader{},
 		},
 		Traces: telemetry.TracesConfig{
@@ -36,3 +59,28 @@ func Telemetry(metricsPort int) telemetry.Config {
 		},
 	}
 }
+
+// LogLevel converts an hclog level name to a zap level. zap has no trace level so trace maps to debug.
+// Empty or unknown levels default to info.
+func LogLevel(level string) zapcore.Level {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "trace", "debug":
+		return zapcore.DebugLevel
+	case "warn":
+		return zapcore.WarnLevel
+	case "error":
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// MetricsLevel converts a level name (none, basic, normal or detailed) to a configtelemetry.Level.
+// Empty or unknown levels default to detailed.
+func MetricsLevel(level string) configtelemetry.Level {
+	var l configtelemetry.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return configtelemetry.LevelDetailed
+	}
+	return l
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/shoenig/test"
 	"github.com/shoenig/test/must"
@@ -18,18 +19,112 @@ import (
 	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/exporters"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/extensions"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/processors"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/types"
+	hcpprovider "github.com/hashicorp/consul-telemetry-collector/internal/otel/providers/hcp"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/redactionprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver"
 	"github.com/hashicorp/hcp-sdk-go/resource"
 )
 
+// testHashKey is the hash key of the HCP redactions of the golden configurations.
+const testHashKey = "test-hash-key"
+
 func Test_newConfigProvider(t *testing.T) {
 	testcases := map[string]struct {
-		testfile    string
-		exporter    *config.ExporterConfig
-		hcpResource *resource.Resource
+		testfile            string
+		exporter            *config.ExporterConfig
+		hcpResource         *resource.Resource
+		healthCheckEndpoint string
+		logLevel            string
+		logJSON             bool
+		metricsAddress      string
+		metricsLevel        string
+		scrapeInterval      time.Duration
+		selfMetricsExporter *config.ExporterConfig
+		cardinalityLimit    *cardinalityprocessor.Config
+		tagExtraction       *envoyreceiver.TagExtractionConfig
+		hcpAggregation      *aggregationprocessor.Config
+		externalAggregation *aggregationprocessor.Config
+		hcpRedaction        *redactionprocessor.Config
+		externalRedaction   *redactionprocessor.Config
+		renames             *processors.Renames
+		stripUnitSuffixes   bool
+		resourceAttributes  *envoyreceiver.ResourceAttributesConfig
+		routes              []config.Route
+		localFilter         *processors.LocalFilter
+		workloadIdentity    hcp.WorkloadIdentity
+		hcpResources        []hcpprovider.Resource
+		proxy               *proxy.Config
 	}{
 		"stock": {
 			testfile: "stock.yaml",
 		},
+		"stock-with-json-logs": {
+			testfile: "stock-with-json-logs.yaml",
+			logLevel: "trace",
+			logJSON:  true,
+		},
+		"stock-with-self-metrics": {
+			testfile:       "stock-with-self-metrics.yaml",
+			metricsAddress: "0.0.0.0:8888",
+			metricsLevel:   "basic",
+			scrapeInterval: 30 * time.Second,
+			selfMetricsExporter: &config.ExporterConfig{
+				ID: component.NewIDWithName(exporters.BaseOtlpExporterID.Type(), "self"),
+				Exporter: &exporters.ExporterConfig{
+					Endpoint: "https://self-metrics-endpoint:4318",
+				},
+			},
+		},
+		"stock-with-tag-extraction": {
+			testfile: "stock-with-tag-extraction.yaml",
+			tagExtraction: &envoyreceiver.TagExtractionConfig{
+				Extractors: []envoyreceiver.TagExtractorConfig{
+					{
+						Name:  "envoy.route",
+						Regex: `^vhost\.(?:.*?\.)?route\.((.+?)\.)`,
+					},
+				},
+			},
+		},
+		"stock-with-stripped-unit-suffixes": {
+			testfile:          "stock-with-stripped-unit-suffixes.yaml",
+			stripUnitSuffixes: true,
+		},
+		"stock-with-resource-attributes": {
+			testfile: "stock-with-resource-attributes.yaml",
+			resourceAttributes: &envoyreceiver.ResourceAttributesConfig{
+				NodeMetadata: map[string]string{
+					"namespace":  "namespace",
+					"partition":  "partition",
+					"datacenter": "consul.datacenter",
+				},
+				Locality: map[string]string{
+					"region": "cloud.region",
+				},
+				BuildVersion: "envoy.version",
+			},
+		},
+		"stock-with-renames": {
+			testfile: "stock-with-renames.yaml",
+			renames: &processors.Renames{
+				Metrics: map[string]string{
+					"cluster.upstream_rq_time": "http.client.request.duration",
+				},
+				Attributes: map[string]string{
+					"envoy.response_code": "http.response.status_code",
+				},
+			},
+		},
+		"stock-without-self-metrics": {
+			testfile:     "stock-without-self-metrics.yaml",
+			metricsLevel: "none",
+		},
 		"stock-with-forwarder": {
 			testfile: "stock-with-forwarder.yaml",
 			exporter: &config.ExporterConfig{
@@ -42,6 +137,49 @@ func Test_newConfigProvider(t *testing.T) {
 				},
 			},
 		},
+		"stock-with-exporter-auth": {
+			testfile: "stock-with-exporter-auth.yaml",
+			exporter: &config.ExporterConfig{
+				ID: exporters.BaseOtlpExporterID,
+				Exporter: &exporters.ExporterConfig{
+					Endpoint: "https://test-forwarder-endpoint:4138",
+				},
+				Authenticator: &config.Authenticator{
+					ID:     extensions.BearerTokenAuthIDFor("otlphttp"),
+					Config: extensions.BearerTokenAuthCfg("", "/var/run/secrets/token"),
+				},
+			},
+		},
+		"stock-with-routes": {
+			testfile: "stock-with-routes.yaml",
+			exporter: &config.ExporterConfig{
+				ID: exporters.BaseOtlpExporterID,
+				Exporter: &exporters.ExporterConfig{
+					Endpoint: "https://test-forwarder-endpoint:4138",
+					Headers: map[string]string{
+						"authorization": "abc123",
+					},
+				},
+			},
+			routes: []config.Route{
+				{
+					Name:      "team-a",
+					Partition: "team-a",
+					Exporter: &config.ExporterConfig{
+						ID: component.NewIDWithName(exporters.BaseOtlpExporterID.Type(), "route-team-a"),
+						Exporter: &exporters.ExporterConfig{
+							Endpoint: "https://team-a-endpoint:4138",
+						},
+					},
+					Redaction: &redactionprocessor.Config{
+						Attributes: []redactionprocessor.Redaction{
+							{Key: "tenant", Action: redactionprocessor.ActionHash},
+						},
+						HashKey: "team-a-key",
+					},
+				},
+			},
+		},
 		"stock-with-forwarder-grpc": {
 			testfile: "stock-with-forwarder-grpc.yaml",
 			exporter: &config.ExporterConfig{
@@ -54,6 +192,25 @@ func Test_newConfigProvider(t *testing.T) {
 				},
 			},
 		},
+		"stock-with-exporter-tls": {
+			testfile: "stock-with-exporter-tls.yaml",
+			exporter: &config.ExporterConfig{
+				ID: exporters.GRPCOtlpExporterID,
+				Exporter: &exporters.ExporterConfig{
+					Endpoint: "https://test-forwarder-endpoint:4138",
+					Headers: map[string]string{
+						"authorization": "abc123",
+					},
+					TLSSetting: &types.TLSClientSetting{
+						CAFile:     "/etc/ssl/ca.pem",
+						CertFile:   "/etc/ssl/client.pem",
+						KeyFile:    "/etc/ssl/client-key.pem",
+						ServerName: "collector.internal",
+						MinVersion: "1.3",
+					},
+				},
+			},
+		},
 		"hcp": {
 			testfile: "hcp.yaml",
 			hcpResource: &resource.Resource{
@@ -78,6 +235,177 @@ func Test_newConfigProvider(t *testing.T) {
 				},
 			},
 		},
+		"hcp-with-proxy": {
+			testfile: "hcp-with-proxy.yaml",
+			hcpResource: &resource.Resource{
+				ID:           "otel-with-cluster",
+				Type:         "hashicorp.consul.cluster",
+				Organization: "00000000-0000-0000-0000-000000000003",
+				Project:      "00000000-0000-0000-0000-000000000004",
+			},
+			exporter: &config.ExporterConfig{
+				ID: exporters.GRPCOtlpExporterID,
+				Exporter: &exporters.ExporterConfig{
+					Endpoint: "https://otel.example.com:4317",
+				},
+			},
+			// the exporters and token requests are given the proxy, which connects to the no_proxy hosts directly.
+			proxy: &proxy.Config{
+				URL:     "http://proxy:3128",
+				NoProxy: []string{".internal"},
+				CAFile:  "testdata/proxy-ca.pem",
+			},
+		},
+		"hcp-with-routes": {
+			testfile: "hcp-with-routes.yaml",
+			hcpResource: &resource.Resource{
+				ID:           "otel-with-cluster",
+				Type:         "hashicorp.consul.cluster",
+				Organization: "00000000-0000-0000-0000-000000000003",
+				Project:      "00000000-0000-0000-0000-000000000004",
+			},
+			exporter: &config.ExporterConfig{
+				ID: exporters.BaseOtlpExporterID,
+				Exporter: &exporters.ExporterConfig{
+					Endpoint: "https://test-forwarder-endpoint:4138",
+				},
+			},
+			routes: []config.Route{
+				{
+					Name:      "team-a",
+					Partition: "team-a",
+					Namespace: "payments",
+					Exporter: &config.ExporterConfig{
+						ID: component.NewIDWithName(exporters.GRPCOtlpExporterID.Type(), "route-team-a"),
+						Exporter: &exporters.ExporterConfig{
+							Endpoint: "team-a-endpoint:4317",
+						},
+					},
+				},
+			},
+		},
+		"hcp-with-local-filter": {
+			testfile: "hcp-with-local-filter.yaml",
+			hcpResource: &resource.Resource{
+				ID:           "otel-cluster",
+				Type:         "hashicorp.consul.cluster",
+				Organization: "00000000-0000-0000-0000-000000000000",
+				Project:      "00000000-0000-0000-0000-000000000001",
+			},
+			localFilter: &processors.LocalFilter{
+				ExcludeMetrics:    []string{"_bucket$"},
+				IncludeAttributes: map[string]string{"partition": "^team-a$"},
+			},
+		},
+		"hcp-with-cardinality-limit": {
+			testfile: "hcp-with-cardinality-limit.yaml",
+			hcpResource: &resource.Resource{
+				ID:           "otel-cluster",
+				Type:         "hashicorp.consul.cluster",
+				Organization: "00000000-0000-0000-0000-000000000000",
+				Project:      "00000000-0000-0000-0000-000000000001",
+			},
+			cardinalityLimit: &cardinalityprocessor.Config{
+				MaxSeriesPerMetric: 500,
+				OverflowAction:     cardinalityprocessor.OverflowActionDrop,
+			},
+		},
+		"hcp-with-aggregation": {
+			testfile: "hcp-with-aggregation.yaml",
+			hcpResource: &resource.Resource{
+				ID:           "otel-cluster",
+				Type:         "hashicorp.consul.cluster",
+				Organization: "00000000-0000-0000-0000-000000000000",
+				Project:      "00000000-0000-0000-0000-000000000001",
+			},
+			cardinalityLimit: &cardinalityprocessor.Config{
+				MaxSeriesPerNode: 1000,
+				OverflowAction:   cardinalityprocessor.OverflowActionAggregate,
+			},
+			hcpAggregation: &aggregationprocessor.Config{},
+			externalAggregation: &aggregationprocessor.Config{
+				DropResourceAttributes: []string{"node.id", "namespace"},
+				Interval:               30 * time.Second,
+				GaugeFunction:          aggregationprocessor.GaugeFunctionMax,
+			},
+		},
+		"hcp-with-redaction": {
+			testfile: "hcp-with-redaction.yaml",
+			hcpResource: &resource.Resource{
+				ID:           "otel-cluster",
+				Type:         "hashicorp.consul.cluster",
+				Organization: "00000000-0000-0000-0000-000000000000",
+				Project:      "00000000-0000-0000-0000-000000000001",
+			},
+			hcpRedaction: &redactionprocessor.Config{
+				ResourceAttributes: []redactionprocessor.Redaction{
+					{Key: "node.id", Action: redactionprocessor.ActionDrop},
+				},
+				Attributes: []redactionprocessor.Redaction{
+					{Key: "envoy.cluster_name", Action: redactionprocessor.ActionHash},
+				},
+				HashKey: testHashKey,
+			},
+			externalRedaction: &redactionprocessor.Config{
+				Attributes: []redactionprocessor.Redaction{
+					{Key: "tenant", Action: redactionprocessor.ActionMask},
+				},
+			},
+		},
+		"hcp-with-workload-identity": {
+			testfile: "hcp-with-workload-identity.yaml",
+			hcpResource: &resource.Resource{
+				ID:           "otel-cluster",
+				Type:         "hashicorp.consul.cluster",
+				Organization: "00000000-0000-0000-0000-000000000000",
+				Project:      "00000000-0000-0000-0000-000000000001",
+			},
+			workloadIdentity: hcp.WorkloadIdentity{
+				ProviderResourceName: "iam/project/00000000-0000-0000-0000-000000000001/service-principal/collector/" +
+					"workload-identity-provider/k8s",
+				TokenFile: "/var/run/secrets/hcp/token",
+			},
+		},
+		"hcp-with-resources": {
+			testfile: "hcp-with-resources.yaml",
+			hcpResource: &resource.Resource{
+				ID:           "otel-cluster",
+				Type:         "hashicorp.consul.cluster",
+				Organization: "00000000-0000-0000-0000-000000000000",
+				Project:      "00000000-0000-0000-0000-000000000001",
+			},
+			hcpResources: []hcpprovider.Resource{
+				{
+					Name: "west",
+					ResourceID: "organization/00000000-0000-0000-0000-000000000000/project/" +
+						"00000000-0000-0000-0000-000000000001/hashicorp.consul.cluster/west-cluster",
+					Client: &hcp.MockClient{
+						MockMetricsEndpoint: "https://hcp-metrics-endpoint-west",
+						MockMetricFilters:   []string{"^west"},
+						MockMetricAttributes: map[string]string{
+							"cluster": "west",
+						},
+					},
+					Credentials: hcp.Credentials{
+						ClientID:     "west-cid",
+						ClientSecret: "west-csec",
+					},
+					Attributes: map[string]string{
+						"consul.cluster": "west",
+					},
+				},
+			},
+		},
+		"hcp-with-health-check": {
+			testfile: "hcp-with-health-check.yaml",
+			hcpResource: &resource.Resource{
+				ID:           "otel-cluster",
+				Type:         "hashicorp.consul.cluster",
+				Organization: "00000000-0000-0000-0000-000000000000",
+				Project:      "00000000-0000-0000-0000-000000000001",
+			},
+			healthCheckEndpoint: "0.0.0.0:13133",
+		},
 	}
 	for name, tc := range testcases {
 		t.Run(name, func(t *testing.T) {
@@ -94,13 +422,41 @@ func Test_newConfigProvider(t *testing.T) {
 				}
 			}
 			c := CollectorCfg{
-				ClientID:       "cid",
-				ClientSecret:   "csec",
-				Client:         mockClient,
-				ResourceID:     resourceURL,
-				ExporterConfig: tc.exporter,
+				ClientID:            "cid",
+				ClientSecret:        "csec",
+				Client:              mockClient,
+				ResourceID:          resourceURL,
+				ExporterConfig:      tc.exporter,
+				HealthCheckEndpoint: tc.healthCheckEndpoint,
+				LogLevel:            tc.logLevel,
+				LogJSON:             tc.logJSON,
+				MetricsAddress:      tc.metricsAddress,
+				MetricsLevel:        tc.metricsLevel,
+				ScrapeInterval:      tc.scrapeInterval,
+				SelfMetricsExporter: tc.selfMetricsExporter,
+				CardinalityLimit:    tc.cardinalityLimit,
+				TagExtraction:       tc.tagExtraction,
+				HCPAggregation:      tc.hcpAggregation,
+				ExternalAggregation: tc.externalAggregation,
+				HCPRedaction:        tc.hcpRedaction,
+				ExternalRedaction:   tc.externalRedaction,
+				Renames:             tc.renames,
+				StripUnitSuffixes:   tc.stripUnitSuffixes,
+				ResourceAttributes:  tc.resourceAttributes,
+				Routes:              tc.routes,
+				LocalFilter:         tc.localFilter,
+				WorkloadIdentity:    tc.workloadIdentity,
+				HCPResources:        tc.hcpResources,
+				Proxy:               tc.proxy,
+			}
+			if tc.workloadIdentity.IsEnabled() {
+				c.ClientID, c.ClientSecret = "", ""
 			}
 
+			if c.HCPRedaction == nil {
+				redaction := processors.DefaultHCPRedaction(testHashKey)
+				c.HCPRedaction = &redaction
+			}
 			c.init()
 
 			provider, err := newProvider(c)
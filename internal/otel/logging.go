@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otel
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config"
+)
+
+// hclogTimeFormat is the timestamp layout hclog uses for JSON logs.
+const hclogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// loggingOptions returns the zap options for the collector logger. When JSON logging is enabled the
+// collector's zap core is replaced by one that writes the same keys and timestamp format as hclog, so the
+// agent and collector logs can be parsed as a single stream.
+func loggingOptions(cfg CollectorCfg) []zap.Option {
+	if !cfg.LogJSON {
+		return nil
+	}
+
+	level := config.LogLevel(cfg.LogLevel)
+	return []zap.Option{
+		zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return hclogJSONCore(zapcore.Lock(os.Stderr), level)
+		}),
+	}
+}
+
+// hclogJSONCore creates a zap core that encodes entries like hclog's JSON format.
+func hclogJSONCore(w zapcore.WriteSyncer, level zapcore.Level) zapcore.Core {
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		TimeKey:        "@timestamp",
+		LevelKey:       "@level",
+		NameKey:        "@module",
+		MessageKey:     "@message",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.TimeEncoderOfLayout(hclogTimeFormat),
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeName:     zapcore.FullNameEncoder,
+	})
+	return zapcore.NewCore(encoder, w, level)
+}
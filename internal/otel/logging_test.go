@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package otel
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/shoenig/test/must"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func Test_hclogJSONCore(t *testing.T) {
+	var hclogBuf, zapBuf bytes.Buffer
+
+	hclog.New(&hclog.LoggerOptions{
+		Name:       "consul-collector",
+		Output:     &hclogBuf,
+		JSONFormat: true,
+	}).Info("hello", "key", "value")
+
+	zap.New(hclogJSONCore(zapcore.AddSync(&zapBuf), zapcore.InfoLevel)).
+		Named("consul-collector").
+		Info("hello", zap.String("key", "value"))
+
+	var hclogLine, zapLine map[string]any
+	must.NoError(t, json.Unmarshal(hclogBuf.Bytes(), &hclogLine))
+	must.NoError(t, json.Unmarshal(zapBuf.Bytes(), &zapLine))
+
+	for key := range hclogLine {
+		must.MapContainsKey(t, zapLine, key)
+	}
+	for _, key := range []string{"@level", "@module", "@message", "key"} {
+		must.Eq(t, hclogLine[key], zapLine[key])
+	}
+
+	_, err := time.Parse(hclogTimeFormat, zapLine["@timestamp"].(string))
+	must.NoError(t, err)
+}
+
+func Test_hclogJSONCore_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zap.New(hclogJSONCore(zapcore.AddSync(&buf), zapcore.WarnLevel))
+
+	logger.Info("dropped")
+	must.Eq(t, 0, buf.Len())
+
+	logger.Warn("kept")
+	must.StrContains(t, buf.String(), `"@level":"warn"`)
+}
+
+func Test_loggingOptions(t *testing.T) {
+	must.Nil(t, loggingOptions(CollectorCfg{}))
+	must.Len(t, 1, loggingOptions(CollectorCfg{LogJSON: true}))
+}
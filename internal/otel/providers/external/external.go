@@ -12,14 +12,40 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/processors"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/providers"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/redactionprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver"
 )
 
 type externalProvider struct {
-	exporterConfig *config.ExporterConfig
-	batchTimeout   time.Duration
-	metricsPort    int
-	envoyPort      int
+	exporterConfig      *config.ExporterConfig
+	batchTimeout        time.Duration
+	envoyPort           int
+	healthCheckEndpoint string
+	pprofEndpoint       string
+	zpagesEndpoint      string
+	proxy               *proxy.Config
+	metricsAddress      string
+	metricsLevel        string
+	scrapeInterval      time.Duration
+	selfMetricsExporter *config.ExporterConfig
+	cardinalityLimit    *cardinalityprocessor.Config
+	hcpAggregation      *aggregationprocessor.Config
+	externalAggregation *aggregationprocessor.Config
+	hcpRedaction        *redactionprocessor.Config
+	externalRedaction   *redactionprocessor.Config
+	renames             *processors.Renames
+	tagExtraction       *envoyreceiver.TagExtractionConfig
+	stripUnitSuffixes   bool
+	resourceAttributes  *envoyreceiver.ResourceAttributesConfig
+	routes              []config.Route
+	localFilter         *processors.LocalFilter
+	logLevel            string
+	logJSON             bool
 }
 
 var _ confmap.Provider = (*externalProvider)(nil)
@@ -27,10 +53,30 @@ var _ confmap.Provider = (*externalProvider)(nil)
 // NewProvider creates a new static in memory configmap provider.
 func NewProvider(exporterConfig *config.ExporterConfig, sharedParams providers.SharedParams) confmap.Provider {
 	e := &externalProvider{
-		exporterConfig: exporterConfig,
-		batchTimeout:   sharedParams.BatchTimeout,
-		envoyPort:      sharedParams.EnvoyPort,
-		metricsPort:    sharedParams.MetricsPort,
+		exporterConfig:      exporterConfig,
+		batchTimeout:        sharedParams.BatchTimeout,
+		envoyPort:           sharedParams.EnvoyPort,
+		healthCheckEndpoint: sharedParams.HealthCheckEndpoint,
+		pprofEndpoint:       sharedParams.PprofEndpoint,
+		zpagesEndpoint:      sharedParams.ZPagesEndpoint,
+		proxy:               sharedParams.Proxy,
+		metricsAddress:      sharedParams.MetricsAddress,
+		metricsLevel:        sharedParams.MetricsLevel,
+		scrapeInterval:      sharedParams.ScrapeInterval,
+		selfMetricsExporter: sharedParams.SelfMetricsExporter,
+		cardinalityLimit:    sharedParams.CardinalityLimit,
+		hcpAggregation:      sharedParams.HCPAggregation,
+		externalAggregation: sharedParams.ExternalAggregation,
+		hcpRedaction:        sharedParams.HCPRedaction,
+		externalRedaction:   sharedParams.ExternalRedaction,
+		renames:             sharedParams.Renames,
+		tagExtraction:       sharedParams.TagExtraction,
+		stripUnitSuffixes:   sharedParams.StripUnitSuffixes,
+		resourceAttributes:  sharedParams.ResourceAttributes,
+		routes:              sharedParams.Routes,
+		localFilter:         sharedParams.LocalFilter,
+		logLevel:            sharedParams.LogLevel,
+		logJSON:             sharedParams.LogJSON,
 	}
 
 	return e
@@ -42,22 +88,45 @@ func (m *externalProvider) Retrieve(_ context.Context, _ string, _ confmap.Watch
 	c := config.NewConfig()
 
 	// 1. Setup Extensions
-	c.Service.Telemetry = config.Telemetry(m.metricsPort)
+	c.Service.Telemetry = config.Telemetry(config.TelemetryParams{
+		MetricsAddress: m.metricsAddress,
+		MetricsLevel:   m.metricsLevel,
+		LogLevel:       m.logLevel,
+		LogJSON:        m.logJSON,
+	})
+
+	externalParams := &config.Params{
+		BatchTimeout:        m.batchTimeout,
+		MetricsAddress:      m.metricsAddress,
+		MetricsLevel:        m.metricsLevel,
+		ScrapeInterval:      m.scrapeInterval,
+		SelfMetricsExporter: m.selfMetricsExporter,
+		CardinalityLimit:    m.cardinalityLimit,
+		HCPAggregation:      m.hcpAggregation,
+		ExternalAggregation: m.externalAggregation,
+		HCPRedaction:        m.hcpRedaction,
+		ExternalRedaction:   m.externalRedaction,
+		Renames:             m.renames,
+		TagExtraction:       m.tagExtraction,
+		StripUnitSuffixes:   m.stripUnitSuffixes,
+		ResourceAttributes:  m.resourceAttributes,
+		LocalFilter:         m.localFilter,
+		Routes:              m.routes,
+		EnvoyListenerPort:   m.envoyPort,
+		Proxy:               m.proxy,
+		HealthCheckEndpoint: m.healthCheckEndpoint,
+		PprofEndpoint:       m.pprofEndpoint,
+		ZPagesEndpoint:      m.zpagesEndpoint,
+	}
 
 	// 2. Setup Extensions
-	extensions := config.ExtensionBuilder()
-	// so far we don't use the params in building extensions for the external provider
-	err := c.EnrichWithExtensions(extensions, nil)
+	extensions := config.ExtensionBuilder(config.OptionalExtensions(externalParams)...)
+	err := c.EnrichWithExtensions(extensions, externalParams)
 	if err != nil {
 		return nil, err
 	}
 
 	// 3. Build external pipeline
-	externalParams := &config.Params{
-		BatchTimeout:      m.batchTimeout,
-		MetricsPort:       m.metricsPort,
-		EnvoyListenerPort: m.envoyPort,
-	}
 
 	// see if this is an empty component.ID
 	if m.exporterConfig != nil {
@@ -71,6 +140,16 @@ func (m *externalProvider) Retrieve(_ context.Context, _ string, _ confmap.Watch
 		return nil, fmt.Errorf("failed to add config to pipeline. provider:external, err: %w", err)
 	}
 
+	// 4. Build the pipelines of the routes
+	if err := c.EnrichWithRoutePipelines(externalParams); err != nil {
+		return nil, fmt.Errorf("failed to add route pipelines. provider:external, err: %w", err)
+	}
+
+	// 5. Build the self metrics pipeline
+	if err := c.EnrichWithSelfMetricsPipeline(externalParams); err != nil {
+		return nil, fmt.Errorf("failed to add self metrics pipeline. provider:external, err: %w", err)
+	}
+
 	conf := confmap.New()
 	err = conf.Marshal(c)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -14,19 +15,68 @@ import (
 
 	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/connectors"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/processors"
 	"github.com/hashicorp/consul-telemetry-collector/internal/otel/providers"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/redactionprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver"
 	"github.com/hashicorp/hcp-sdk-go/resource"
 )
 
 type hcpProvider struct {
-	exporterConfig *config.ExporterConfig
-	client         hcp.TelemetryClient
-	clientID       string
-	clientSecret   string
-	shutdownCh     chan struct{}
-	metricsPort    int
-	batchTimeout   time.Duration
-	envoyPort      int
+	exporterConfig      *config.ExporterConfig
+	client              hcp.TelemetryClient
+	credentials         hcp.Credentials
+	resources           []Resource
+	shutdownCh          chan struct{}
+	batchTimeout        time.Duration
+	envoyPort           int
+	healthCheckEndpoint string
+	pprofEndpoint       string
+	zpagesEndpoint      string
+	proxy               *proxy.Config
+	metricsAddress      string
+	metricsLevel        string
+	scrapeInterval      time.Duration
+	selfMetricsExporter *config.ExporterConfig
+	cardinalityLimit    *cardinalityprocessor.Config
+	hcpAggregation      *aggregationprocessor.Config
+	externalAggregation *aggregationprocessor.Config
+	hcpRedaction        *redactionprocessor.Config
+	externalRedaction   *redactionprocessor.Config
+	renames             *processors.Renames
+	tagExtraction       *envoyreceiver.TagExtractionConfig
+	stripUnitSuffixes   bool
+	resourceAttributes  *envoyreceiver.ResourceAttributesConfig
+	routes              []config.Route
+	localFilter         *processors.LocalFilter
+	logLevel            string
+	logJSON             bool
+
+	mu sync.Mutex
+	// loaded are the credentials of the last retrieved configuration, the ones of the default resource first and
+	// then the ones of the resources.
+	loaded []loadedCredentials
+}
+
+// Resource is an HCP resource, in addition to the one of the URI, the envoy metrics with its resource attribute
+// values are exported to.
+type Resource struct {
+	// Name identifies the resource in the ids of its components, like otlphttp/hcp-<name>.
+	Name        string
+	ResourceID  string
+	Client      hcp.TelemetryClient
+	Credentials hcp.Credentials
+	// Attributes are the resource attribute values of the metrics exported to the resource.
+	Attributes map[string]string
+}
+
+type loadedCredentials struct {
+	clientID     string
+	clientSecret string
 }
 
 const scheme = "hcp"
@@ -34,23 +84,45 @@ const schemePrefix = scheme + ":"
 
 var _ confmap.Provider = (*hcpProvider)(nil)
 
-// NewProvider creates a new static in memory configmap provider.
+// NewProvider creates a new configmap provider for the HCP pipeline. The configuration changes when the credentials
+// read from files are rotated so the oauth2client extension picks them up. The resources get pipelines of their
+// own, and an hcp: uri without a resource only exports to them.
 func NewProvider(
 	exporterConfig *config.ExporterConfig,
 	client hcp.TelemetryClient,
-	clientID,
-	clientSecret string,
+	credentials hcp.Credentials,
+	resources []Resource,
 	sharedParams providers.SharedParams,
 ) confmap.Provider {
 	p := &hcpProvider{
-		exporterConfig: exporterConfig,
-		client:         client,
-		clientID:       clientID,
-		clientSecret:   clientSecret,
-		shutdownCh:     make(chan struct{}),
-		batchTimeout:   sharedParams.BatchTimeout,
-		metricsPort:    sharedParams.MetricsPort,
-		envoyPort:      sharedParams.EnvoyPort,
+		exporterConfig:      exporterConfig,
+		client:              client,
+		credentials:         credentials,
+		resources:           resources,
+		shutdownCh:          make(chan struct{}),
+		batchTimeout:        sharedParams.BatchTimeout,
+		envoyPort:           sharedParams.EnvoyPort,
+		healthCheckEndpoint: sharedParams.HealthCheckEndpoint,
+		pprofEndpoint:       sharedParams.PprofEndpoint,
+		zpagesEndpoint:      sharedParams.ZPagesEndpoint,
+		proxy:               sharedParams.Proxy,
+		metricsAddress:      sharedParams.MetricsAddress,
+		metricsLevel:        sharedParams.MetricsLevel,
+		scrapeInterval:      sharedParams.ScrapeInterval,
+		selfMetricsExporter: sharedParams.SelfMetricsExporter,
+		cardinalityLimit:    sharedParams.CardinalityLimit,
+		hcpAggregation:      sharedParams.HCPAggregation,
+		externalAggregation: sharedParams.ExternalAggregation,
+		hcpRedaction:        sharedParams.HCPRedaction,
+		externalRedaction:   sharedParams.ExternalRedaction,
+		renames:             sharedParams.Renames,
+		tagExtraction:       sharedParams.TagExtraction,
+		stripUnitSuffixes:   sharedParams.StripUnitSuffixes,
+		resourceAttributes:  sharedParams.ResourceAttributes,
+		routes:              sharedParams.Routes,
+		localFilter:         sharedParams.LocalFilter,
+		logLevel:            sharedParams.LogLevel,
+		logJSON:             sharedParams.LogJSON,
 	}
 
 	return p
@@ -65,31 +137,109 @@ func (m *hcpProvider) Retrieve(
 		return nil, fmt.Errorf("%q uri is not supported by %q provider", uri, m.Scheme())
 	}
 
-	r, err := resource.FromString(strings.TrimLeft(uri, schemePrefix))
+	// An hcp: uri without a resource only exports to the resources of the provider.
+	var resourceID string
+	var telemetryCfg *hcp.TelemetryConfig
+	if rawResource := strings.TrimPrefix(uri, schemePrefix); rawResource != "" {
+		r, err := resource.FromString(rawResource)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %q uri as HCP resource URL %w", uri, err)
+		}
+		resourceID = r.String()
+
+		if m.client != nil {
+			cfg, err := m.client.TelemetryConfig(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve the HCP telemetry configuration: %w", err)
+			}
+			telemetryCfg = &cfg
+		}
+	}
+
+	loaded, err := m.loadCredentials(resourceID != "")
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse %q uri as HCP resource URL %w", uri, err)
+		return nil, err
+	}
+	m.mu.Lock()
+	m.loaded = loaded
+	m.mu.Unlock()
+
+	var clientID, clientSecret string
+	if resourceID != "" {
+		clientID, clientSecret = loaded[0].clientID, loaded[0].clientSecret
+		loaded = loaded[1:]
+	}
+	hcpResources := make([]config.HCPResource, 0, len(m.resources))
+	for i, r := range m.resources {
+		resourceCfg, err := r.Client.TelemetryConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the HCP telemetry configuration of resource %q: %w", r.Name,
+				err)
+		}
+		hcpResources = append(hcpResources, config.HCPResource{
+			Name:             r.Name,
+			ResourceID:       r.ResourceID,
+			TelemetryConfig:  &resourceCfg,
+			ClientID:         loaded[i].clientID,
+			ClientSecret:     loaded[i].clientSecret,
+			WorkloadIdentity: r.Credentials.WorkloadIdentity,
+			Attributes:       r.Attributes,
+		})
 	}
 
 	// Create new empty configuration
 	c := config.NewConfig()
 
 	// 1. Setup Telemetery
-	c.Service.Telemetry = config.Telemetry(m.metricsPort)
+	c.Service.Telemetry = config.Telemetry(config.TelemetryParams{
+		MetricsAddress: m.metricsAddress,
+		MetricsLevel:   m.metricsLevel,
+		LogLevel:       m.logLevel,
+		LogJSON:        m.logJSON,
+	})
 
 	// 2. Setup Extensions
-	extensions := config.ExtensionBuilder(config.WithExtOauthClientID)
-	// in this set of extension IDs we want the WithExtOauthClientID which requires the params to build
-	// the actual extension.
+	// in this set of extension IDs we want the WithExtOauthClientID, or the WithExtWorkloadIdentityID, which
+	// requires the params to build the actual extension.
 	hcpParams := &config.Params{
-		ExporterConfig:    m.exporterConfig,
-		Client:            m.client,
-		ClientID:          m.clientID,
-		ClientSecret:      m.clientSecret,
-		ResourceID:        r.String(),
-		BatchTimeout:      m.batchTimeout,
-		MetricsPort:       m.metricsPort,
-		EnvoyListenerPort: m.envoyPort,
+		ExporterConfig:      m.exporterConfig,
+		TelemetryConfig:     telemetryCfg,
+		ClientID:            clientID,
+		ClientSecret:        clientSecret,
+		WorkloadIdentity:    m.credentials.WorkloadIdentity,
+		ResourceID:          resourceID,
+		HCPResources:        hcpResources,
+		BatchTimeout:        m.batchTimeout,
+		MetricsAddress:      m.metricsAddress,
+		MetricsLevel:        m.metricsLevel,
+		ScrapeInterval:      m.scrapeInterval,
+		SelfMetricsExporter: m.selfMetricsExporter,
+		CardinalityLimit:    m.cardinalityLimit,
+		HCPAggregation:      m.hcpAggregation,
+		ExternalAggregation: m.externalAggregation,
+		HCPRedaction:        m.hcpRedaction,
+		ExternalRedaction:   m.externalRedaction,
+		Renames:             m.renames,
+		TagExtraction:       m.tagExtraction,
+		StripUnitSuffixes:   m.stripUnitSuffixes,
+		ResourceAttributes:  m.resourceAttributes,
+		LocalFilter:         m.localFilter,
+		EnvoyListenerPort:   m.envoyPort,
+		Proxy:               m.proxy,
+		HealthCheckEndpoint: m.healthCheckEndpoint,
+		PprofEndpoint:       m.pprofEndpoint,
+		ZPagesEndpoint:      m.zpagesEndpoint,
 	}
+	var extensionOpts []config.Opts
+	if resourceID != "" {
+		authOpt := config.WithExtOauthClientID
+		if m.credentials.WorkloadIdentity.IsEnabled() {
+			authOpt = config.WithExtWorkloadIdentityID
+		}
+		extensionOpts = append(extensionOpts, authOpt)
+	}
+	extensionOpts = append(extensionOpts, config.OptionalExtensions(hcpParams)...)
+	extensions := config.ExtensionBuilder(extensionOpts...)
 	err = c.EnrichWithExtensions(extensions, hcpParams)
 	if err != nil {
 		return nil, err
@@ -97,14 +247,28 @@ func (m *hcpProvider) Retrieve(
 
 	// 3. Build pipeline configurations and enrich the config with them
 	// 3. A: Build HCP pipeline
-	hcpPipelineCfg := config.PipelineConfigBuilder(hcpParams)
+	if resourceID != "" {
+		hcpPipelineCfg := config.PipelineConfigBuilder(hcpParams)
 
-	// Set the filter processor on the config
-	hcpPipelineCfg.Processors = config.ProcessorBuilder(config.WithFilterProcessor, config.WithResourceProcessor)
+		// Set the filter processor on the config. The optional processors go after it so they only operate on the
+		// metrics forwarded to HCP.
+		processorOpts := append([]config.Opts{config.WithFilterProcessor}, config.OptionalProcessors(hcpParams)...)
+		processorOpts = append(processorOpts, config.WithResourceProcessor)
+		hcpPipelineCfg.Processors = config.ProcessorBuilder(processorOpts...)
 
-	hcpID := component.NewIDWithName(component.DataTypeMetrics, "hcp")
-	err = c.EnrichWithPipelineCfg(hcpPipelineCfg, hcpParams, hcpID)
-	if err != nil {
+		// With resources the routing connector sends the metrics no resource matches to the HCP pipeline.
+		if len(hcpResources) > 0 {
+			hcpPipelineCfg.Receivers = []component.ID{connectors.HCPRoutingConnectorID}
+		}
+
+		err = c.EnrichWithPipelineCfg(hcpPipelineCfg, hcpParams, config.HCPPipelineID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 3. A': Build the pipelines of the HCP resources and the routing to them.
+	if err := c.EnrichWithHCPResources(hcpParams); err != nil {
 		return nil, err
 	}
 
@@ -116,10 +280,25 @@ func (m *hcpProvider) Retrieve(
 	// An improvement here would be to separate the service stanza creation from the HCP or External generators. This
 	// would allow component configuration to happen separately from the service stanza and removing repeated work.
 	externalParams := &config.Params{
-		ExporterConfig:    m.exporterConfig,
-		BatchTimeout:      m.batchTimeout,
-		MetricsPort:       m.metricsPort,
-		EnvoyListenerPort: m.envoyPort,
+		ExporterConfig:      m.exporterConfig,
+		BatchTimeout:        m.batchTimeout,
+		MetricsAddress:      m.metricsAddress,
+		MetricsLevel:        m.metricsLevel,
+		ScrapeInterval:      m.scrapeInterval,
+		SelfMetricsExporter: m.selfMetricsExporter,
+		CardinalityLimit:    m.cardinalityLimit,
+		HCPAggregation:      m.hcpAggregation,
+		ExternalAggregation: m.externalAggregation,
+		HCPRedaction:        m.hcpRedaction,
+		ExternalRedaction:   m.externalRedaction,
+		Renames:             m.renames,
+		TagExtraction:       m.tagExtraction,
+		StripUnitSuffixes:   m.stripUnitSuffixes,
+		ResourceAttributes:  m.resourceAttributes,
+		LocalFilter:         m.localFilter,
+		Routes:              m.routes,
+		EnvoyListenerPort:   m.envoyPort,
+		Proxy:               m.proxy,
 	}
 	externalCfg := config.PipelineConfigBuilder(externalParams)
 	externalID := component.NewID(component.DataTypeMetrics)
@@ -128,11 +307,24 @@ func (m *hcpProvider) Retrieve(
 		return nil, err
 	}
 
+	// 3. C: Build the pipelines of the routes of the external pipeline.
+	if err := c.EnrichWithRoutePipelines(externalParams); err != nil {
+		return nil, err
+	}
+
+	// 3. D: Build the self metrics pipeline. Like the external pipeline it must be included so the merged service
+	// stanza keeps it.
+	if err := c.EnrichWithSelfMetricsPipeline(externalParams); err != nil {
+		return nil, err
+	}
+
 	go func() {
 		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ctx.Done():
+				return
 			case <-m.shutdownCh:
 				return
 			case <-ticker.C:
@@ -161,6 +353,40 @@ func (m *hcpProvider) Shutdown(_ context.Context) error {
 	return nil
 }
 
+// loadCredentials loads the credentials of the default resource, when withDefault is set, and of the resources.
+func (m *hcpProvider) loadCredentials(withDefault bool) ([]loadedCredentials, error) {
+	loaded := make([]loadedCredentials, 0, len(m.resources)+1)
+	if withDefault {
+		clientID, clientSecret, err := m.credentials.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the HCP credentials: %w", err)
+		}
+		loaded = append(loaded, loadedCredentials{clientID: clientID, clientSecret: clientSecret})
+	}
+	for _, r := range m.resources {
+		clientID, clientSecret, err := r.Credentials.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the HCP credentials of resource %q: %w", r.Name, err)
+		}
+		loaded = append(loaded, loadedCredentials{clientID: clientID, clientSecret: clientSecret})
+	}
+	return loaded, nil
+}
+
+// configChange reports whether the credentials changed since the configuration was retrieved. Credentials that
+// can't be read, for example while a file is being replaced, keep the current configuration.
 func (m *hcpProvider) configChange() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	loaded, err := m.loadCredentials(len(m.loaded) > len(m.resources))
+	if err != nil {
+		return false
+	}
+	for i := range loaded {
+		if loaded[i] != m.loaded[i] {
+			return true
+		}
+	}
 	return false
 }
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/providers"
+)
+
+const resourceURL = "hcp:organization/00000000-0000-0000-0000-000000000000/project/" +
+	"00000000-0000-0000-0000-000000000001/hashicorp.consul.cluster/otel-cluster"
+
+func Test_RotatedCredentials(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "client_secret")
+	must.NoError(t, os.WriteFile(secretFile, []byte("csec\n"), 0o600))
+
+	p := NewProvider(nil, &hcp.MockClient{MockMetricsEndpoint: "https://hcp-metrics-endpoint"}, hcp.Credentials{
+		ClientID:         "cid",
+		ClientSecretFile: secretFile,
+	}, nil, providers.SharedParams{}).(*hcpProvider)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	clientSecret := func() any {
+		retrieved, err := p.Retrieve(ctx, resourceURL, func(*confmap.ChangeEvent) {})
+		must.NoError(t, err)
+		conf, err := retrieved.AsConf()
+		must.NoError(t, err)
+		return conf.Get("extensions::oauth2client/hcp::client_secret")
+	}
+
+	must.Eq(t, "csec", clientSecret())
+	must.False(t, p.configChange())
+
+	must.NoError(t, os.WriteFile(secretFile, []byte("rotated\n"), 0o600))
+	must.True(t, p.configChange())
+	must.Eq(t, "rotated", clientSecret())
+	must.False(t, p.configChange())
+
+	// a missing file keeps the current configuration.
+	must.NoError(t, os.Remove(secretFile))
+	must.False(t, p.configChange())
+}
+
+func Test_ResourcesOnly(t *testing.T) {
+	p := NewProvider(nil, nil, hcp.Credentials{}, []Resource{
+		{
+			Name:        "west",
+			ResourceID:  strings.TrimPrefix(resourceURL, schemePrefix),
+			Client:      &hcp.MockClient{MockMetricsEndpoint: "https://hcp-metrics-endpoint-west"},
+			Credentials: hcp.Credentials{ClientID: "west-cid", ClientSecret: "west-csec"},
+			Attributes:  map[string]string{"consul.cluster": "west"},
+		},
+	}, providers.SharedParams{}).(*hcpProvider)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	retrieved, err := p.Retrieve(ctx, schemePrefix, func(*confmap.ChangeEvent) {})
+	must.NoError(t, err)
+	conf, err := retrieved.AsConf()
+	must.NoError(t, err)
+
+	must.Eq(t, "west-csec", conf.Get("extensions::oauth2client/hcp-west::client_secret"))
+	must.Nil(t, conf.Get("extensions::oauth2client/hcp"))
+	must.Nil(t, conf.Get("service::pipelines::metrics/hcp"))
+	must.NotNil(t, conf.Get("service::pipelines::metrics/hcp-west"))
+	must.SliceEmpty(t, conf.Get("connectors::routing/hcp::default_pipelines").([]any))
+	must.False(t, p.configChange())
+}
@@ -3,11 +3,63 @@
 
 package providers
 
-import "time"
+import (
+	"time"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/processors"
+	"github.com/hashicorp/consul-telemetry-collector/internal/proxy"
+	"github.com/hashicorp/consul-telemetry-collector/processors/aggregationprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/processors/redactionprocessor"
+	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver"
+)
 
 // SharedParams holds shared configuration parameters
 type SharedParams struct {
 	EnvoyPort    int
-	MetricsPort  int
 	BatchTimeout time.Duration
+	// MetricsAddress is the host:port the collector's internal metrics are served on.
+	MetricsAddress string
+	// MetricsLevel is the level name of the collector's internal metrics.
+	MetricsLevel string
+	// ScrapeInterval is how often the collector scrapes its own metrics.
+	ScrapeInterval time.Duration
+	// SelfMetricsExporter, when set, exports the collector's own metrics in a dedicated pipeline.
+	SelfMetricsExporter *config.ExporterConfig
+	// CardinalityLimit, when set, caps the unique series of the envoy metrics pipelines.
+	CardinalityLimit *cardinalityprocessor.Config
+	// HCPAggregation, when set, aggregates the metrics of the HCP pipeline across resource attributes.
+	HCPAggregation *aggregationprocessor.Config
+	// ExternalAggregation, when set, aggregates the metrics of the external pipeline across resource attributes.
+	ExternalAggregation *aggregationprocessor.Config
+	// HCPRedaction, when set, redacts the attributes of the metrics of the HCP pipeline. The collector sets it to
+	// hashing the node.id when none is configured.
+	HCPRedaction *redactionprocessor.Config
+	// ExternalRedaction, when set, redacts the attributes of the metrics of the external pipeline.
+	ExternalRedaction *redactionprocessor.Config
+	// Renames, when set, renames the envoy metrics and attributes of the envoy metrics pipelines.
+	Renames *processors.Renames
+	// TagExtraction, when set, extracts the dimensions envoy embeds in stat names into attributes.
+	TagExtraction *envoyreceiver.TagExtractionConfig
+	// StripUnitSuffixes removes the unit suffixes from the envoy metric names.
+	StripUnitSuffixes bool
+	// ResourceAttributes, when set, selects the envoy node properties set as resource attributes.
+	ResourceAttributes *envoyreceiver.ResourceAttributesConfig
+	// LocalFilter, when set, filters the envoy metrics with the user's allow and deny lists.
+	LocalFilter *processors.LocalFilter
+	// Routes, when set, export the envoy metrics of a Consul partition or namespace to the exporter of its route.
+	Routes []config.Route
+	// HealthCheckEndpoint enables the health check extension on this address when set.
+	HealthCheckEndpoint string
+	// PprofEndpoint enables the pprof extension on this address when set.
+	PprofEndpoint string
+	// ZPagesEndpoint enables the zpages extension on this address when set.
+	ZPagesEndpoint string
+	// Proxy, when enabled, is the proxy the connections of the exporters and auth extensions go through.
+	Proxy *proxy.Config
+	// LogLevel is the hclog level name the collector logs at.
+	LogLevel string
+	// LogJSON sets the collector log encoding to JSON.
+	LogJSON bool
 }
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package proxy
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestConfig_Apply(t *testing.T) {
+	var proxiedHost string
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+	}))
+	t.Cleanup(proxySrv.Close)
+
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	caFile := filepath.Join(t.TempDir(), "proxy-ca.pem")
+	must.NoError(t, os.WriteFile(caFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+
+	cfg := &Config{URL: proxySrv.URL, NoProxy: []string{"direct.internal"}, CAFile: caFile}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	must.NoError(t, cfg.Apply(transport))
+	client := &http.Client{Transport: transport}
+
+	// requests go through the proxy unless their host is in the no_proxy list.
+	resp, err := client.Get("http://metrics.example.com/v1/metrics")
+	must.NoError(t, err)
+	must.NoError(t, resp.Body.Close())
+	must.Eq(t, "metrics.example.com", proxiedHost)
+
+	proxiedHost = ""
+	_, err = client.Get("http://direct.internal/v1/metrics")
+	must.Error(t, err)
+	must.Eq(t, "", proxiedHost)
+
+	// the CA is trusted along with the system roots, loopback addresses are never proxied.
+	resp, err = client.Get(srv.URL)
+	must.NoError(t, err)
+	must.NoError(t, resp.Body.Close())
+	must.NotNil(t, transport.TLSClientConfig.RootCAs)
+
+	notPEM := filepath.Join(t.TempDir(), "not-pem")
+	must.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+	err = (&Config{URL: proxySrv.URL, CAFile: notPEM}).Apply(http.DefaultTransport.(*http.Transport).Clone())
+	must.ErrorContains(t, err, "no PEM certificates")
+
+	// a transport is left unchanged when no proxy is configured.
+	transport = http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig := transport.TLSClientConfig
+	must.NoError(t, (&Config{CAFile: caFile}).Apply(transport))
+	must.True(t, tlsConfig == transport.TLSClientConfig)
+}
+
+func TestConfig_DialContext(t *testing.T) {
+	backend, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	t.Cleanup(func() { backend.Close() })
+	go func() {
+		for {
+			conn, err := backend.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("hello"))
+			conn.Close()
+		}
+	}()
+
+	var authorization, tunneled string
+	proxySrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Proxy-Authorization")
+		if r.Method != http.MethodConnect || authorization == "" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		tunneled = r.Host
+		upstream, err := net.Dial("tcp", backend.Addr().String())
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		_, _ = io.Copy(conn, upstream)
+	}))
+	t.Cleanup(proxySrv.Close)
+	proxyURL, err := url.Parse(proxySrv.URL)
+	must.NoError(t, err)
+
+	// connections are tunneled through the proxy with the credentials of its URL.
+	proxyURL.User = url.UserPassword("user", "password")
+	cfg := &Config{URL: proxyURL.String(), NoProxy: []string{"direct.internal"}}
+	conn, err := cfg.DialContext(context.Background(), "collector.example.com:4317")
+	must.NoError(t, err)
+	b, err := io.ReadAll(conn)
+	must.NoError(t, err)
+	must.NoError(t, conn.Close())
+	must.Eq(t, "hello", string(b))
+	must.Eq(t, "collector.example.com:4317", tunneled)
+	must.Eq(t, "Basic dXNlcjpwYXNzd29yZA==", authorization)
+
+	// the proxy's refusal fails the connection.
+	proxyURL.User = nil
+	cfg.URL = proxyURL.String()
+	_, err = cfg.DialContext(context.Background(), "collector.example.com:4317")
+	must.ErrorContains(t, err, "407")
+
+	// hosts in the no_proxy list and loopback addresses are dialed directly.
+	tunneled = ""
+	conn, err = cfg.DialContext(context.Background(), backend.Addr().String())
+	must.NoError(t, err)
+	must.NoError(t, conn.Close())
+	must.Eq(t, "", tunneled)
+}
@@ -4,18 +4,42 @@
 package prometheus
 
 import (
+	prompb "github.com/prometheus/client_model/go"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
 // Builder is an OTLP metric builder.
 type Builder struct {
-	identity pcommon.Resource
-	metrics  []pmetric.Metric
+	identity      pcommon.Resource
+	metrics       []pmetric.Metric
+	tagExtractors []TagExtractor
+	// stripUnitSuffixes removes the unit and _total suffixes from the metric names.
+	stripUnitSuffixes bool
+}
+
+// Option configures a Builder.
+type Option func(*Builder)
+
+// WithTagExtraction moves the dimensions envoy embeds in stat names, like the cluster name in
+// cluster.<cluster_name>.upstream_rq_<response_code>, into data point attributes using the extractors.
+func WithTagExtraction(extractors ...TagExtractor) Option {
+	return func(b *Builder) {
+		b.tagExtractors = append(b.tagExtractors, extractors...)
+	}
+}
+
+// WithUnitSuffixStripping removes the unit suffixes, like _bytes or _ms, and the _total suffix of counters from the
+// metric names following the OpenTelemetry Prometheus compatibility specification. Without it the builder is strict
+// and keeps the original names. The unit is inferred from the suffixes either way.
+func WithUnitSuffixStripping() Option {
+	return func(b *Builder) {
+		b.stripUnitSuffixes = true
+	}
 }
 
 // NewBuilder creates a new OTLP metric builder to convert prometheus metrics to OTLP metrics.
-func NewBuilder(identityLabels map[string]string) *Builder {
+func NewBuilder(identityLabels map[string]string, opts ...Option) *Builder {
 	resource := pcommon.NewResource()
 
 	for k, v := range identityLabels {
@@ -24,6 +48,9 @@ func NewBuilder(identityLabels map[string]string) *Builder {
 	b := &Builder{
 		identity: resource,
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
 
 	return b
 }
@@ -59,3 +86,21 @@ func generateMetricsDefinition(resourceLabels pcommon.Resource, metricsRef pmetr
 	metricsRef.CopyTo(scopedMetrics.Metrics())
 	return metricsDefintion
 }
+
+// describe returns the OTLP metric name and unit of the family and the tags extracted from its name.
+func (b *Builder) describe(family *prompb.MetricFamily) (string, string, map[string]string) {
+	name, tags := extractTags(family.GetName(), b.tagExtractors)
+	name, unit := inferUnit(normalizeName(name), family.GetType(), b.stripUnitSuffixes)
+	return name, unit, tags
+}
+
+// putAttributes sets the metric labels and the extracted tags on the data point attributes. Labels take
+// precedence over tags of the same name since envoy may already have extracted them.
+func putAttributes(attrs pcommon.Map, labels []*prompb.LabelPair, tags map[string]string) {
+	for k, v := range tags {
+		attrs.PutStr(k, v)
+	}
+	for _, labelPair := range labels {
+		attrs.PutStr(labelPair.GetName(), labelPair.GetValue())
+	}
+}
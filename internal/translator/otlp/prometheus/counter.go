@@ -12,17 +12,17 @@ import (
 func (b *Builder) AddCounter(family *prompb.MetricFamily) {
 	otlpMetric := pmetric.NewMetric()
 
-	otlpMetric.SetName(normalizeName(family.GetName()))
+	name, unit, tags := b.describe(family)
+	otlpMetric.SetName(name)
 	otlpMetric.SetDescription(family.GetHelp())
+	otlpMetric.SetUnit(unit)
 	emptySum := otlpMetric.SetEmptySum()
 	emptySum.SetIsMonotonic(true)
 	emptySum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
 	for _, metric := range family.GetMetric() {
 		dp := emptySum.DataPoints().AppendEmpty()
 
-		for _, labelPair := range metric.GetLabel() {
-			dp.Attributes().PutStr(labelPair.GetName(), labelPair.GetValue())
-		}
+		putAttributes(dp.Attributes(), metric.GetLabel(), tags)
 
 		dp.SetTimestamp(timestampFromMs(metric.GetTimestampMs()))
 		dp.SetDoubleValue(metric.GetCounter().GetValue())
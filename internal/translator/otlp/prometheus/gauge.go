@@ -12,15 +12,15 @@ import (
 func (b *Builder) AddGauge(family *prompb.MetricFamily) {
 	otlpMetric := pmetric.NewMetric()
 
-	otlpMetric.SetName(normalizeName(family.GetName()))
+	name, unit, tags := b.describe(family)
+	otlpMetric.SetName(name)
 	otlpMetric.SetDescription(family.GetHelp())
+	otlpMetric.SetUnit(unit)
 	emptyGauge := otlpMetric.SetEmptyGauge()
 	for _, metric := range family.GetMetric() {
 		dp := emptyGauge.DataPoints().AppendEmpty()
 
-		for _, labelPair := range metric.GetLabel() {
-			dp.Attributes().PutStr(labelPair.GetName(), labelPair.GetValue())
-		}
+		putAttributes(dp.Attributes(), metric.GetLabel(), tags)
 
 		dp.SetTimestamp(timestampFromMs(metric.GetTimestampMs()))
 		dp.SetDoubleValue(metric.GetGauge().GetValue())
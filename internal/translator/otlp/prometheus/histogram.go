@@ -14,8 +14,10 @@ import (
 func (b *Builder) AddHistogram(family *prompb.MetricFamily) {
 	otlpMetric := pmetric.NewMetric()
 
-	otlpMetric.SetName(normalizeName(family.GetName()))
+	name, unit, tags := b.describe(family)
+	otlpMetric.SetName(name)
 	otlpMetric.SetDescription(family.GetHelp())
+	otlpMetric.SetUnit(unit)
 
 	emptyHistogram := otlpMetric.SetEmptyHistogram()
 	emptyHistogram.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
@@ -35,9 +37,7 @@ func (b *Builder) AddHistogram(family *prompb.MetricFamily) {
 		dp.BucketCounts().FromRaw(bucket)
 		dp.ExplicitBounds().FromRaw(bounds)
 
-		for _, labelPair := range metric.GetLabel() {
-			dp.Attributes().PutStr(labelPair.GetName(), labelPair.GetValue())
-		}
+		putAttributes(dp.Attributes(), metric.GetLabel(), tags)
 
 		dp.SetTimestamp(timestampFromMs(metric.GetTimestampMs()))
 	}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TagExtractor extracts a dimension envoy embeds in a stat name into an attribute. It follows the semantics of
+// envoy's stats tag extraction: the regex must have at least one capture group. The first capture group is
+// removed from the stat name and the value of the attribute is the second capture group if there is one,
+// otherwise the first.
+type TagExtractor struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+// NewTagExtractor creates a TagExtractor adding the name attribute with the value extracted by the regex.
+func NewTagExtractor(name, regex string) (TagExtractor, error) {
+	if name == "" {
+		return TagExtractor{}, errors.New("tag extractor name must not be empty")
+	}
+
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return TagExtractor{}, fmt.Errorf("failed to compile tag extractor %s: %w", name, err)
+	}
+	if re.NumSubexp() == 0 {
+		return TagExtractor{}, fmt.Errorf("tag extractor %s regex %q must have a capture group", name, regex)
+	}
+
+	return TagExtractor{name: name, regex: re}, nil
+}
+
+// defaultTagExtractors are envoy's default tag extraction regexes rewritten for RE2, which does not support the
+// lookaheads envoy uses. See
+// https://github.com/envoyproxy/envoy/blob/main/source/common/config/well_known_names.cc
+var defaultTagExtractors = []struct {
+	name  string
+	regex string
+}{
+	// cluster.(<cluster_name>.)*
+	{"envoy.cluster_name", `^cluster\.((.+?)\.)`},
+	// listener.[<address>.]http.(<stat_prefix>.)*
+	{"envoy.http_conn_manager_prefix", `^listener\.(?:.*?\.)?http\.((.*?)\.)`},
+	// http.(<stat_prefix>.)*
+	{"envoy.http_conn_manager_prefix", `^http\.((.*?)\.)`},
+	// http.[<stat_prefix>.]user_agent.(<user_agent>.)<base_stat>
+	{"envoy.http_user_agent", `^http\.(?:.*?\.)?user_agent\.((.+?)\.)\w+?$`},
+	// vhost.[<virtual host name>.]vcluster.(<virtual_cluster_name>.)<base_stat>
+	{"envoy.virtual_cluster_name", `^vhost\.(?:.*?\.)?vcluster\.((.+?)\.)\w+?$`},
+	// http.[<stat_prefix>.]fault.(<downstream_cluster>.)<base_stat>
+	{"envoy.fault_downstream_cluster", `^http\.(?:.*?\.)?fault\.((.+?)\.)\w+?$`},
+	// listener.[<address>.]ssl.cipher(.<cipher>)
+	{"envoy.ssl_cipher", `^listener\.(?:.*?\.)?ssl\.cipher(\.(.+?))$`},
+	// cluster.[<cluster_name>.]ssl.ciphers(.<cipher>)
+	{"envoy.ssl_cipher_suite", `^cluster\.(?:.*?\.)?ssl\.ciphers(\.(.+?))$`},
+	// cluster.[<route_target_cluster>.]grpc.[<grpc_service>.](<grpc_method>.)<base_stat>
+	{"envoy.grpc_bridge_method", `^cluster\.(?:.*?\.)?grpc\..*\.((.+?)\.)\w+?$`},
+	// cluster.[<route_target_cluster>.]grpc.(<grpc_service>.)*
+	{"envoy.grpc_bridge_service", `^cluster\.(?:.*?\.)?grpc\.((.+?)\.)`},
+	// vhost.(<virtual host name>.)*
+	{"envoy.virtual_host_name", `^vhost\.((.*?)\.)`},
+	// *_rq_(<response_code_class>)xx
+	{"envoy.response_code_class", `_rq_((\d))xx$`},
+	// *_rq(_<response_code>)
+	{"envoy.response_code", `_rq(_(\d{3}))$`},
+	// listener.(<address>.)*
+	{"envoy.listener_address", `^listener\.(((?:[_.[:digit:]]*|[_\[\]aAbBcCdDeEfF[:digit:]]*))\.)`},
+	// tcp.(<stat_prefix>.)<base_stat>
+	{"envoy.tcp_prefix", `^tcp\.((.*?)\.)\w+?$`},
+	// listener_manager.(worker_<id>.)*
+	{"envoy.worker_id", `^listener_manager\.(worker_(\d+)\.)`},
+}
+
+// DefaultTagExtractors returns envoy's default tag extractors.
+func DefaultTagExtractors() []TagExtractor {
+	extractors := make([]TagExtractor, 0, len(defaultTagExtractors))
+	for _, d := range defaultTagExtractors {
+		extractor, err := NewTagExtractor(d.name, d.regex)
+		if err != nil {
+			// the default extractors are static so this is a programming error.
+			panic(err)
+		}
+		extractors = append(extractors, extractor)
+	}
+	return extractors
+}
+
+// span is a range of a stat name to remove.
+type span struct {
+	start, end int
+}
+
+// extractTags applies every extractor to the stat name. As in envoy each extractor matches the original name,
+// and the first capture group of every match is removed from it once all the extractors ran. If several
+// extractors produce the same attribute the first one wins.
+func extractTags(name string, extractors []TagExtractor) (string, map[string]string) {
+	if len(extractors) == 0 {
+		return name, nil
+	}
+
+	var tags map[string]string
+	var remove []span
+	for _, extractor := range extractors {
+		if _, ok := tags[extractor.name]; ok {
+			continue
+		}
+
+		match := extractor.regex.FindStringSubmatchIndex(name)
+		if match == nil || match[2] < 0 {
+			continue
+		}
+
+		// the value is the second capture group if there is one, otherwise the first.
+		value := name[match[2]:match[3]]
+		if len(match) > 4 && match[4] >= 0 {
+			value = name[match[4]:match[5]]
+		}
+
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[extractor.name] = value
+		remove = append(remove, span{start: match[2], end: match[3]})
+	}
+
+	return removeSpans(name, remove), tags
+}
+
+// removeSpans removes the possibly overlapping spans from s.
+func removeSpans(s string, spans []span) string {
+	if len(spans) == 0 {
+		return s
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var sb strings.Builder
+	pos := 0
+	for _, sp := range spans {
+		if sp.start > pos {
+			sb.WriteString(s[pos:sp.start])
+		}
+		if sp.end > pos {
+			pos = sp.end
+		}
+	}
+	sb.WriteString(s[pos:])
+	return sb.String()
+}
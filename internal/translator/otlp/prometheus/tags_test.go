@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package prometheus
+
+import (
+	"testing"
+
+	prompb "github.com/prometheus/client_model/go"
+	"github.com/shoenig/test/must"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewTagExtractor(t *testing.T) {
+	for name, tc := range map[string]struct {
+		tag   string
+		regex string
+		err   string
+	}{
+		"valid": {
+			tag:   "envoy.route",
+			regex: `^vhost\.(?:.*?\.)?route\.((.+?)\.)`,
+		},
+		"empty name": {
+			regex: `^vhost\.((.*?)\.)`,
+			err:   "tag extractor name must not be empty",
+		},
+		"invalid regex": {
+			tag:   "envoy.route",
+			regex: `^vhost\.((.*?\.)`,
+			err:   "failed to compile tag extractor envoy.route",
+		},
+		"no capture group": {
+			tag:   "envoy.route",
+			regex: `^vhost\.`,
+			err:   "must have a capture group",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewTagExtractor(tc.tag, tc.regex)
+			if tc.err != "" {
+				must.ErrorContains(t, err, tc.err)
+				return
+			}
+			must.NoError(t, err)
+		})
+	}
+}
+
+func Test_extractTags(t *testing.T) {
+	custom, err := NewTagExtractor("envoy.route", `^vhost\.(?:.*?\.)?route\.((.+?)\.)`)
+	must.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		stat       string
+		extractors []TagExtractor
+		name       string
+		tags       map[string]string
+	}{
+		"no extractors": {
+			stat: "cluster.api.upstream_rq_200",
+			name: "cluster.api.upstream_rq_200",
+		},
+		"cluster and response code": {
+			stat:       "cluster.api.upstream_rq_200",
+			extractors: DefaultTagExtractors(),
+			name:       "cluster.upstream_rq",
+			tags: map[string]string{
+				"envoy.cluster_name":  "api",
+				"envoy.response_code": "200",
+			},
+		},
+		"response code class": {
+			stat:       "cluster.api.upstream_rq_5xx",
+			extractors: DefaultTagExtractors(),
+			name:       "cluster.upstream_rq_xx",
+			tags: map[string]string{
+				"envoy.cluster_name":        "api",
+				"envoy.response_code_class": "5",
+			},
+		},
+		"listener http prefix": {
+			stat:       "listener.0.0.0.0_20000.http.public_listener.downstream_rq_2xx",
+			extractors: DefaultTagExtractors(),
+			name:       "listener.http.downstream_rq_xx",
+			tags: map[string]string{
+				"envoy.listener_address":         "0.0.0.0_20000",
+				"envoy.http_conn_manager_prefix": "public_listener",
+				"envoy.response_code_class":      "2",
+			},
+		},
+		"ssl cipher": {
+			stat:       "cluster.api.ssl.ciphers.ECDHE-RSA-AES128-GCM-SHA256",
+			extractors: DefaultTagExtractors(),
+			name:       "cluster.ssl.ciphers",
+			tags: map[string]string{
+				"envoy.cluster_name":     "api",
+				"envoy.ssl_cipher_suite": "ECDHE-RSA-AES128-GCM-SHA256",
+			},
+		},
+		"no match": {
+			stat:       "server.live",
+			extractors: DefaultTagExtractors(),
+			name:       "server.live",
+		},
+		"user supplied": {
+			stat:       "vhost.local_app.route.api.upstream_rq_total",
+			extractors: append(DefaultTagExtractors(), custom),
+			name:       "vhost.route.upstream_rq_total",
+			tags: map[string]string{
+				"envoy.virtual_host_name": "local_app",
+				"envoy.route":             "api",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			name, tags := extractTags(tc.stat, tc.extractors)
+			must.Eq(t, tc.name, name)
+			must.MapEq(t, tc.tags, tags)
+		})
+	}
+}
+
+func TestBuilder_TagExtraction(t *testing.T) {
+	b := NewBuilder(nil, WithTagExtraction(DefaultTagExtractors()...))
+	b.AddCounter(&prompb.MetricFamily{
+		Name: proto.String("cluster.api.upstream_rq_200"),
+		Type: prompb.MetricType_COUNTER.Enum(),
+		Metric: []*prompb.Metric{
+			{
+				Label: []*prompb.LabelPair{
+					{Name: proto.String("envoy.cluster_name"), Value: proto.String("labelled")},
+					{Name: proto.String("local_cluster"), Value: proto.String("web")},
+				},
+				Counter: &prompb.Counter{Value: proto.Float64(3)},
+			},
+		},
+	})
+
+	md := b.Build()
+	must.Eq(t, 1, md.MetricCount())
+	metric := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	must.Eq(t, "cluster.upstream_rq", metric.Name())
+
+	// labels take precedence over extracted tags.
+	must.MapEq(t, map[string]any{
+		"envoy.cluster_name":  "labelled",
+		"envoy.response_code": "200",
+		"local_cluster":       "web",
+	}, metric.Sum().DataPoints().At(0).Attributes().AsRaw())
+}
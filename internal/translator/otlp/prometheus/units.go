@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package prometheus
+
+import (
+	"strings"
+
+	prompb "github.com/prometheus/client_model/go"
+)
+
+const (
+	// suffixEnvoyTime is the suffix of the envoy histograms recording durations in milliseconds, like
+	// cluster.upstream_rq_time. It is not a unit so it is never stripped.
+	suffixEnvoyTime = "_time"
+
+	unitMilliseconds  = "ms"
+	unitDimensionless = "1"
+)
+
+// unitSuffixes map the metric name suffixes to their UCUM unit.
+// See https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/compatibility/prometheus_and_openmetrics.md
+var unitSuffixes = []struct {
+	suffix string
+	unit   string
+}{
+	{"_milliseconds", unitMilliseconds},
+	{"_seconds", "s"},
+	{"_bytes", "By"},
+	{"_ratio", unitDimensionless},
+	{"_ms", unitMilliseconds},
+}
+
+// inferUnit returns the unit of the metric inferred from the suffixes of its name. When strip is set the
+// _total suffix of counters and the unit suffix are removed from the name following the OpenTelemetry
+// Prometheus compatibility specification, otherwise the name is returned unchanged.
+func inferUnit(name string, metricType prompb.MetricType, strip bool) (string, string) {
+	base := name
+	total := false
+	if metricType == prompb.MetricType_COUNTER && strings.HasSuffix(base, suffixTotal) && base != suffixTotal {
+		base = strings.TrimSuffix(base, suffixTotal)
+		total = true
+	}
+
+	unit := ""
+	for _, u := range unitSuffixes {
+		if strings.HasSuffix(base, u.suffix) && base != u.suffix {
+			base = strings.TrimSuffix(base, u.suffix)
+			unit = u.unit
+			break
+		}
+	}
+
+	if unit == "" {
+		switch {
+		case metricType == prompb.MetricType_HISTOGRAM && strings.HasSuffix(base, suffixEnvoyTime):
+			unit = unitMilliseconds
+		case total:
+			unit = unitDimensionless
+		}
+	}
+
+	if !strip {
+		return name, unit
+	}
+	return base, unit
+}
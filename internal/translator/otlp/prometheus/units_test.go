@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package prometheus
+
+import (
+	"testing"
+
+	prompb "github.com/prometheus/client_model/go"
+	"github.com/shoenig/test/must"
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_inferUnit(t *testing.T) {
+	for name, tc := range map[string]struct {
+		metric     string
+		metricType prompb.MetricType
+		unit       string
+		stripped   string
+	}{
+		"milliseconds": {
+			metric:     "http.downstream_cx_length_ms",
+			metricType: prompb.MetricType_HISTOGRAM,
+			unit:       "ms",
+			stripped:   "http.downstream_cx_length",
+		},
+		"seconds": {
+			metric:     "process_cpu_seconds_total",
+			metricType: prompb.MetricType_COUNTER,
+			unit:       "s",
+			stripped:   "process_cpu",
+		},
+		"bytes counter": {
+			metric:     "cluster.upstream_cx_rx_bytes_total",
+			metricType: prompb.MetricType_COUNTER,
+			unit:       "By",
+			stripped:   "cluster.upstream_cx_rx",
+		},
+		"bytes gauge": {
+			metric:     "cluster.upstream_cx_rx_bytes_buffered",
+			metricType: prompb.MetricType_GAUGE,
+			stripped:   "cluster.upstream_cx_rx_bytes_buffered",
+		},
+		"total counter": {
+			metric:     "http.downstream_rq_total",
+			metricType: prompb.MetricType_COUNTER,
+			unit:       "1",
+			stripped:   "http.downstream_rq",
+		},
+		"total gauge": {
+			metric:     "server.total",
+			metricType: prompb.MetricType_GAUGE,
+			stripped:   "server.total",
+		},
+		"envoy time histogram": {
+			metric:     "cluster.upstream_rq_time",
+			metricType: prompb.MetricType_HISTOGRAM,
+			unit:       "ms",
+			stripped:   "cluster.upstream_rq_time",
+		},
+		"no unit": {
+			metric:     "cluster.upstream_cx_active",
+			metricType: prompb.MetricType_GAUGE,
+			stripped:   "cluster.upstream_cx_active",
+		},
+		"only suffix": {
+			metric:     "_bytes",
+			metricType: prompb.MetricType_GAUGE,
+			stripped:   "_bytes",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			metric, unit := inferUnit(tc.metric, tc.metricType, false)
+			must.Eq(t, tc.metric, metric)
+			must.Eq(t, tc.unit, unit)
+
+			metric, unit = inferUnit(tc.metric, tc.metricType, true)
+			must.Eq(t, tc.stripped, metric)
+			must.Eq(t, tc.unit, unit)
+		})
+	}
+}
+
+func TestBuilder_Units(t *testing.T) {
+	family := &prompb.MetricFamily{
+		Name: proto.String("cluster.upstream_cx_rx_bytes_total"),
+		Type: prompb.MetricType_COUNTER.Enum(),
+		Metric: []*prompb.Metric{
+			{Counter: &prompb.Counter{Value: proto.Float64(1024)}},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		opts   []Option
+		metric string
+	}{
+		"strict": {
+			metric: "cluster.upstream_cx_rx_bytes_total",
+		},
+		"stripped": {
+			opts:   []Option{WithUnitSuffixStripping()},
+			metric: "cluster.upstream_cx_rx",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b := NewBuilder(nil, tc.opts...)
+			b.AddCounter(family)
+
+			metric := b.Build().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+			must.Eq(t, tc.metric, metric.Name())
+			must.Eq(t, "By", metric.Unit())
+		})
+	}
+}
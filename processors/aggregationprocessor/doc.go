@@ -0,0 +1,14 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package aggregationprocessor implements a processor that aggregates metrics across resource attributes, for
+// example to export per service totals instead of a series per proxy (the node.id resource attribute).
+//
+// The processor keeps the latest data point of every series it receives and exports their aggregate each
+// interval: sums are added, gauges are combined with the gauge function and histograms are merged across the
+// series that only differ by the dropped resource attributes. Cumulative sums, gauges and histograms keep
+// contributing their last value until they have not been reported for max_staleness, delta sums only contribute
+// to the interval they were reported in. The start time of a cumulative aggregate is reset whenever the series
+// it aggregates change, since its value jumps when a series appears or goes stale. Exponential histograms and
+// summaries can't be aggregated and are passed through unchanged.
+package aggregationprocessor
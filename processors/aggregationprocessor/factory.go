@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aggregationprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+)
+
+const (
+	// ID is the identifier for the processor.
+	ID = "aggregation"
+
+	// GaugeFunctionSum adds the gauges of the aggregated series.
+	GaugeFunctionSum = "sum"
+
+	// GaugeFunctionMax keeps the largest gauge of the aggregated series.
+	GaugeFunctionMax = "max"
+
+	// GaugeFunctionLast keeps the most recent gauge of the aggregated series.
+	GaugeFunctionLast = "last"
+
+	// nodeIDKey is the resource attribute identifying the proxy that reported a metric.
+	nodeIDKey = "node.id"
+
+	defaultInterval     = time.Minute
+	defaultMaxStaleness = 5 * time.Minute
+)
+
+// Config is the configuration for the aggregation processor.
+type Config struct {
+	// DropResourceAttributes are the resource attributes the metrics are aggregated across.
+	DropResourceAttributes []string `mapstructure:"drop_resource_attributes"`
+
+	// Interval is how often the aggregated metrics are exported. The processor exports on its own ticker, since
+	// the series it holds are aggregated whether or not they were reported since the last export. The batch
+	// processor after it can delay them by up to its timeout, so the default matches the default batch timeout.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// MaxStaleness is how long the last value of a series keeps contributing to the aggregate after it was
+	// last reported. It does not apply to delta sums.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
+
+	// GaugeFunction is how the gauges of the aggregated series are combined: sum, max or last. Sums are right for
+	// gauges counting things, like active connections, but not for ratios or states.
+	GaugeFunction string `mapstructure:"gauge_function"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that the configuration is usable.
+func (c *Config) Validate() error {
+	if len(c.DropResourceAttributes) == 0 {
+		return errors.New("drop_resource_attributes must not be empty")
+	}
+	if c.Interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+	if c.MaxStaleness < c.Interval {
+		return fmt.Errorf("max_staleness %s must not be shorter than the interval %s", c.MaxStaleness, c.Interval)
+	}
+	switch c.GaugeFunction {
+	case GaugeFunctionSum, GaugeFunctionMax, GaugeFunctionLast:
+		return nil
+	default:
+		return fmt.Errorf("gauge_function %q must be %s, %s or %s", c.GaugeFunction, GaugeFunctionSum,
+			GaugeFunctionMax, GaugeFunctionLast)
+	}
+}
+
+// NewFactory creates a new aggregation processor factory.
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		ID,
+		CreateDefaultConfig,
+		processor.WithMetrics(createMetrics, component.StabilityLevelDevelopment),
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for the processor.
+func CreateDefaultConfig() component.Config {
+	return &Config{
+		DropResourceAttributes: []string{nodeIDKey},
+		Interval:               defaultInterval,
+		MaxStaleness:           defaultMaxStaleness,
+		GaugeFunction:          GaugeFunctionSum,
+	}
+}
+
+func createMetrics(
+	_ context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (processor.Metrics, error) {
+	if nextConsumer == nil {
+		return nil, component.ErrNilNextConsumer
+	}
+
+	return newAggregator(cfg.(*Config), set, nextConsumer), nil
+}
@@ -0,0 +1,545 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aggregationprocessor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/zap"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/datapoint"
+)
+
+// aggregator keeps the latest data point of every series and exports their aggregate each interval.
+type aggregator struct {
+	cfg          *Config
+	logger       *zap.Logger
+	nextConsumer consumer.Metrics
+	now          func() time.Time
+	dropped      map[string]struct{}
+	mergeGauge   func(into, from pmetric.NumberDataPoint)
+
+	mu sync.Mutex
+	// series maps the key of a series as it was reported, including the dropped resource attributes, to its
+	// latest data point.
+	series map[string]*series
+	// cumulative maps the key of a cumulative aggregate to the series it aggregated when it was last exported.
+	cumulative map[string]*cumulativeAggregate
+
+	shutdownCh chan struct{}
+	doneCh     chan struct{}
+}
+
+// series is the latest data point of a reported series and the aggregate it contributes to.
+type series struct {
+	resource pcommon.Resource
+	scope    pcommon.InstrumentationScope
+	// metric holds the single data point of the series.
+	metric   pmetric.Metric
+	lastSeen time.Time
+
+	// resourceKey, scopeKey, metricKey and attributesKey identify the aggregate the series contributes to.
+	resourceKey   string
+	scopeKey      string
+	metricKey     string
+	attributesKey string
+}
+
+// cumulativeAggregate is the start time of a cumulative sum or histogram aggregate, and the series it aggregated
+// when it was last exported.
+type cumulativeAggregate struct {
+	series    string
+	start     pcommon.Timestamp
+	timestamp pcommon.Timestamp
+}
+
+var _ processor.Metrics = (*aggregator)(nil)
+
+func newAggregator(cfg *Config, set processor.CreateSettings, nextConsumer consumer.Metrics) *aggregator {
+	dropped := make(map[string]struct{}, len(cfg.DropResourceAttributes))
+	for _, attr := range cfg.DropResourceAttributes {
+		dropped[attr] = struct{}{}
+	}
+
+	mergeGauge := datapoint.MergeSum
+	switch cfg.GaugeFunction {
+	case GaugeFunctionMax:
+		mergeGauge = datapoint.MergeMax
+	case GaugeFunctionLast:
+		mergeGauge = datapoint.MergeGauge
+	}
+
+	return &aggregator{
+		cfg:          cfg,
+		logger:       set.Logger,
+		nextConsumer: nextConsumer,
+		now:          time.Now,
+		dropped:      dropped,
+		mergeGauge:   mergeGauge,
+		series:       make(map[string]*series),
+		cumulative:   make(map[string]*cumulativeAggregate),
+	}
+}
+
+func (a *aggregator) Start(_ context.Context, _ component.Host) error {
+	a.shutdownCh = make(chan struct{})
+	a.doneCh = make(chan struct{})
+	go a.run()
+	return nil
+}
+
+// Shutdown stops the export loop and exports the metrics aggregated since the last interval.
+func (a *aggregator) Shutdown(ctx context.Context) error {
+	if a.doneCh == nil {
+		return nil
+	}
+
+	close(a.shutdownCh)
+	<-a.doneCh
+	return a.export(ctx)
+}
+
+func (a *aggregator) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (a *aggregator) run() {
+	defer close(a.doneCh)
+
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.shutdownCh:
+			return
+		case <-ticker.C:
+			if err := a.export(context.Background()); err != nil {
+				a.logger.Warn("failed to export aggregated metrics", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ConsumeMetrics records the sums, gauges and histograms to be aggregated. The metrics that can't be
+// aggregated are passed through unchanged.
+func (a *aggregator) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	a.mu.Lock()
+	passthrough := a.record(a.now(), md)
+	a.mu.Unlock()
+
+	if !passthrough {
+		return nil
+	}
+
+	unsupported := pmetric.NewMetrics()
+	md.CopyTo(unsupported)
+	unsupported.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			sm.Metrics().RemoveIf(aggregatable)
+			return sm.Metrics().Len() == 0
+		})
+		return rm.ScopeMetrics().Len() == 0
+	})
+	return a.nextConsumer.ConsumeMetrics(ctx, unsupported)
+}
+
+// record keeps the latest data point of every series in the metrics. It returns true if some metrics can't be
+// aggregated.
+func (a *aggregator) record(now time.Time, md pmetric.Metrics) bool {
+	passthrough := false
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		sourceKey := attributesKey(rm.Resource().Attributes())
+
+		resource := pcommon.NewResource()
+		rm.Resource().CopyTo(resource)
+		resource.Attributes().RemoveIf(func(k string, _ pcommon.Value) bool {
+			_, ok := a.dropped[k]
+			return ok
+		})
+		resourceKey := attributesKey(resource.Attributes())
+
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sm := sms.At(j)
+			scope := pcommon.NewInstrumentationScope()
+			sm.Scope().CopyTo(scope)
+			scopeKey := scope.Name() + "\x00" + scope.Version()
+
+			ms := sm.Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := ms.At(k)
+				if !aggregatable(m) {
+					passthrough = true
+					continue
+				}
+
+				metricKey := metricKey(m)
+				for _, dp := range splitDataPoints(m) {
+					s := &series{
+						resource:      resource,
+						scope:         scope,
+						metric:        dp,
+						lastSeen:      now,
+						resourceKey:   resourceKey,
+						scopeKey:      scopeKey,
+						metricKey:     metricKey,
+						attributesKey: attributesKey(dataPointAttributes(dp)),
+					}
+					a.recordSeries(strings.Join([]string{sourceKey, scopeKey, metricKey, s.attributesKey}, "\x01"), s)
+				}
+			}
+		}
+	}
+
+	return passthrough
+}
+
+// recordSeries replaces the data point of the series unless it is older than the one already recorded. Delta sums
+// are added to the data point recorded within the interval.
+func (a *aggregator) recordSeries(key string, s *series) {
+	existing, ok := a.series[key]
+	if !ok {
+		a.series[key] = s
+		return
+	}
+
+	if isDelta(s.metric) {
+		a.merge(existing.metric, s.metric)
+		existing.lastSeen = s.lastSeen
+		return
+	}
+
+	if timestamp(s.metric) >= timestamp(existing.metric) {
+		a.series[key] = s
+	}
+}
+
+// export sends the aggregate of the recorded series to the next consumer.
+func (a *aggregator) export(ctx context.Context) error {
+	a.mu.Lock()
+	md := a.aggregate(a.now())
+	a.mu.Unlock()
+
+	if md.DataPointCount() == 0 {
+		return nil
+	}
+	return a.nextConsumer.ConsumeMetrics(ctx, md)
+}
+
+// aggregate merges the recorded series that only differ by the dropped resource attributes. Stale series are
+// forgotten before aggregating and delta sums once they are aggregated. The start time of the cumulative
+// aggregates is reset when the series they aggregate changed since they were last exported.
+func (a *aggregator) aggregate(now time.Time) pmetric.Metrics {
+	cutoff := now.Add(-a.cfg.MaxStaleness)
+	keys := make([]string, 0, len(a.series))
+	for key, s := range a.series {
+		if s.lastSeen.Before(cutoff) {
+			delete(a.series, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	md := pmetric.NewMetrics()
+	resources := make(map[string]pmetric.ScopeMetricsSlice)
+	scopes := make(map[string]pmetric.MetricSlice)
+	metrics := make(map[string]pmetric.Metric)
+	aggregates := make(map[string]pmetric.Metric)
+	// members holds the keys of the series of every aggregate.
+	members := make(map[string][]string)
+	// order holds the aggregates in the order they were created, their key and the metric they belong to.
+	var order []aggregateMetric
+	for _, key := range keys {
+		s := a.series[key]
+		if isDelta(s.metric) {
+			delete(a.series, key)
+		}
+
+		aggregateKey := strings.Join([]string{s.resourceKey, s.scopeKey, s.metricKey, s.attributesKey}, "\x01")
+		members[aggregateKey] = append(members[aggregateKey], key)
+		if aggregate, ok := aggregates[aggregateKey]; ok {
+			a.merge(aggregate, s.metric)
+			continue
+		}
+
+		sms, ok := resources[s.resourceKey]
+		if !ok {
+			rm := md.ResourceMetrics().AppendEmpty()
+			s.resource.CopyTo(rm.Resource())
+			sms = rm.ScopeMetrics()
+			resources[s.resourceKey] = sms
+		}
+
+		scopeKey := s.resourceKey + "\x01" + s.scopeKey
+		ms, ok := scopes[scopeKey]
+		if !ok {
+			sm := sms.AppendEmpty()
+			s.scope.CopyTo(sm.Scope())
+			ms = sm.Metrics()
+			scopes[scopeKey] = ms
+		}
+
+		metricKey := scopeKey + "\x01" + s.metricKey
+		m, ok := metrics[metricKey]
+		if !ok {
+			m = ms.AppendEmpty()
+			emptyCopy(s.metric, m)
+			metrics[metricKey] = m
+		}
+
+		// the aggregate starts as a copy of the first series and is appended to the metric once complete.
+		aggregate := pmetric.NewMetric()
+		s.metric.CopyTo(aggregate)
+		aggregates[aggregateKey] = aggregate
+		order = append(order, aggregateMetric{key: aggregateKey, aggregate: aggregate, metric: m})
+	}
+
+	for _, o := range order {
+		if isCumulative(o.aggregate) {
+			a.setStart(o.key, strings.Join(members[o.key], "\x02"), o.aggregate)
+		}
+		moveDataPoints(o.aggregate, o.metric)
+	}
+	for key := range a.cumulative {
+		if _, ok := aggregates[key]; !ok {
+			delete(a.cumulative, key)
+		}
+	}
+
+	return md
+}
+
+// aggregateMetric is an aggregate and the metric its data point is appended to.
+type aggregateMetric struct {
+	key       string
+	aggregate pmetric.Metric
+	metric    pmetric.Metric
+}
+
+// setStart sets the start time of the cumulative aggregate. It is the earliest start time of its series when it is
+// first exported, and the time it was last exported when its series changed since: a series that appears or goes
+// stale makes the aggregate jump, which must not be read as an increase.
+func (a *aggregator) setStart(key, series string, aggregate pmetric.Metric) {
+	start, ts := timestamps(aggregate)
+	c, ok := a.cumulative[key]
+	switch {
+	case !ok:
+		c = &cumulativeAggregate{series: series, start: start}
+		a.cumulative[key] = c
+	case c.series != series:
+		c.series = series
+		c.start = c.timestamp
+		if c.start > ts {
+			c.start = ts
+		}
+	}
+	c.timestamp = ts
+	setStartTimestamp(aggregate, c.start)
+}
+
+// merge merges the single data point of from into the single data point of into. Sums are added and gauges are
+// combined with the gauge function.
+func (a *aggregator) merge(into, from pmetric.Metric) {
+	switch into.Type() {
+	case pmetric.MetricTypeSum:
+		datapoint.MergeSum(into.Sum().DataPoints().At(0), from.Sum().DataPoints().At(0))
+	case pmetric.MetricTypeGauge:
+		a.mergeGauge(into.Gauge().DataPoints().At(0), from.Gauge().DataPoints().At(0))
+	case pmetric.MetricTypeHistogram:
+		datapoint.MergeHistogram(into.Histogram().DataPoints().At(0), from.Histogram().DataPoints().At(0))
+	case pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary, pmetric.MetricTypeEmpty:
+	}
+}
+
+// aggregatable reports whether the data points of the metric can be aggregated.
+func aggregatable(m pmetric.Metric) bool {
+	switch m.Type() {
+	case pmetric.MetricTypeSum, pmetric.MetricTypeGauge, pmetric.MetricTypeHistogram:
+		return true
+	case pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary, pmetric.MetricTypeEmpty:
+	}
+	return false
+}
+
+// metricKey identifies a metric by its name, type and the properties that must match for its data points to be
+// merged.
+func metricKey(m pmetric.Metric) string {
+	parts := []string{m.Name(), m.Unit(), m.Type().String()}
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		parts = append(parts, m.Sum().AggregationTemporality().String())
+		if m.Sum().IsMonotonic() {
+			parts = append(parts, "monotonic")
+		}
+	case pmetric.MetricTypeHistogram:
+		parts = append(parts, m.Histogram().AggregationTemporality().String())
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary,
+		pmetric.MetricTypeEmpty:
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// attributesKey identifies a set of attributes.
+func attributesKey(attrs pcommon.Map) string {
+	pairs := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		pairs = append(pairs, k+"="+v.AsString())
+		return true
+	})
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "\x00")
+}
+
+// emptyCopy copies the metric to dest without its data points.
+func emptyCopy(m, dest pmetric.Metric) {
+	dest.SetName(m.Name())
+	dest.SetDescription(m.Description())
+	dest.SetUnit(m.Unit())
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		sum := dest.SetEmptySum()
+		sum.SetIsMonotonic(m.Sum().IsMonotonic())
+		sum.SetAggregationTemporality(m.Sum().AggregationTemporality())
+	case pmetric.MetricTypeGauge:
+		dest.SetEmptyGauge()
+	case pmetric.MetricTypeHistogram:
+		dest.SetEmptyHistogram().SetAggregationTemporality(m.Histogram().AggregationTemporality())
+	case pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary, pmetric.MetricTypeEmpty:
+	}
+}
+
+// splitDataPoints returns a copy of the metric per data point.
+func splitDataPoints(m pmetric.Metric) []pmetric.Metric {
+	var split []pmetric.Metric
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		for i := 0; i < m.Sum().DataPoints().Len(); i++ {
+			dp := pmetric.NewMetric()
+			emptyCopy(m, dp)
+			m.Sum().DataPoints().At(i).CopyTo(dp.Sum().DataPoints().AppendEmpty())
+			split = append(split, dp)
+		}
+	case pmetric.MetricTypeGauge:
+		for i := 0; i < m.Gauge().DataPoints().Len(); i++ {
+			dp := pmetric.NewMetric()
+			emptyCopy(m, dp)
+			m.Gauge().DataPoints().At(i).CopyTo(dp.Gauge().DataPoints().AppendEmpty())
+			split = append(split, dp)
+		}
+	case pmetric.MetricTypeHistogram:
+		for i := 0; i < m.Histogram().DataPoints().Len(); i++ {
+			dp := pmetric.NewMetric()
+			emptyCopy(m, dp)
+			m.Histogram().DataPoints().At(i).CopyTo(dp.Histogram().DataPoints().AppendEmpty())
+			split = append(split, dp)
+		}
+	case pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary, pmetric.MetricTypeEmpty:
+	}
+	return split
+}
+
+// moveDataPoints moves the data points of from to the end of the data points of into.
+func moveDataPoints(from, into pmetric.Metric) {
+	switch into.Type() {
+	case pmetric.MetricTypeSum:
+		from.Sum().DataPoints().MoveAndAppendTo(into.Sum().DataPoints())
+	case pmetric.MetricTypeGauge:
+		from.Gauge().DataPoints().MoveAndAppendTo(into.Gauge().DataPoints())
+	case pmetric.MetricTypeHistogram:
+		from.Histogram().DataPoints().MoveAndAppendTo(into.Histogram().DataPoints())
+	case pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary, pmetric.MetricTypeEmpty:
+	}
+}
+
+func dataPointAttributes(m pmetric.Metric) pcommon.Map {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().At(0).Attributes()
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().At(0).Attributes()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().At(0).Attributes()
+	case pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary, pmetric.MetricTypeEmpty:
+	}
+	return pcommon.NewMap()
+}
+
+func timestamp(m pmetric.Metric) pcommon.Timestamp {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		return m.Sum().DataPoints().At(0).Timestamp()
+	case pmetric.MetricTypeGauge:
+		return m.Gauge().DataPoints().At(0).Timestamp()
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().DataPoints().At(0).Timestamp()
+	case pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary, pmetric.MetricTypeEmpty:
+	}
+	return 0
+}
+
+// timestamps returns the start time and timestamp of the single data point of a sum or histogram.
+func timestamps(m pmetric.Metric) (pcommon.Timestamp, pcommon.Timestamp) {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		dp := m.Sum().DataPoints().At(0)
+		return dp.StartTimestamp(), dp.Timestamp()
+	case pmetric.MetricTypeHistogram:
+		dp := m.Histogram().DataPoints().At(0)
+		return dp.StartTimestamp(), dp.Timestamp()
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary,
+		pmetric.MetricTypeEmpty:
+	}
+	return 0, 0
+}
+
+func setStartTimestamp(m pmetric.Metric, start pcommon.Timestamp) {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		m.Sum().DataPoints().At(0).SetStartTimestamp(start)
+	case pmetric.MetricTypeHistogram:
+		m.Histogram().DataPoints().At(0).SetStartTimestamp(start)
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary,
+		pmetric.MetricTypeEmpty:
+	}
+}
+
+// isCumulative reports whether the metric is a cumulative sum or histogram, whose data points count from their
+// start time.
+func isCumulative(m pmetric.Metric) bool {
+	switch m.Type() {
+	case pmetric.MetricTypeSum, pmetric.MetricTypeHistogram:
+		return !isDelta(m)
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary,
+		pmetric.MetricTypeEmpty:
+	}
+	return false
+}
+
+// isDelta reports whether the metric is a delta sum or histogram whose data points only count towards the
+// interval they are reported in.
+func isDelta(m pmetric.Metric) bool {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		return m.Sum().AggregationTemporality() == pmetric.AggregationTemporalityDelta
+	case pmetric.MetricTypeHistogram:
+		return m.Histogram().AggregationTemporality() == pmetric.AggregationTemporalityDelta
+	case pmetric.MetricTypeGauge, pmetric.MetricTypeExponentialHistogram, pmetric.MetricTypeSummary,
+		pmetric.MetricTypeEmpty:
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aggregationprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+const clusterKey = "envoy.cluster"
+
+func testAggregator(t *testing.T, cfg *Config) (*aggregator, *consumertest.MetricsSink, *time.Time) {
+	t.Helper()
+
+	sink := new(consumertest.MetricsSink)
+	a := newAggregator(cfg, processortest.NewNopCreateSettings(), sink)
+	now := time.Unix(1700000000, 0)
+	a.now = func() time.Time { return now }
+	return a, sink, &now
+}
+
+func defaultConfig() *Config {
+	return CreateDefaultConfig().(*Config)
+}
+
+// counter creates a cumulative counter reported by the node of the cluster.
+func counter(cluster, node string, value int64, ts time.Time) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr(clusterKey, cluster)
+	rm.Resource().Attributes().PutStr(nodeIDKey, node)
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("cluster.upstream_rq")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.Attributes().PutStr("envoy.response_code", "200")
+	dp.SetIntValue(value)
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	return md
+}
+
+func TestAggregator_Counters(t *testing.T) {
+	a, sink, now := testAggregator(t, defaultConfig())
+	ctx := context.Background()
+
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("api", "node-1", 5, *now)))
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("api", "node-2", 3, *now)))
+	// a newer report of the same series replaces the previous one rather than being added.
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("api", "node-1", 7, now.Add(time.Second))))
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("web", "node-3", 1, *now)))
+	must.Eq(t, 0, sink.DataPointCount())
+
+	must.NoError(t, a.export(ctx))
+	must.Len(t, 1, sink.AllMetrics())
+
+	md := sink.AllMetrics()[0]
+	must.Eq(t, 2, md.ResourceMetrics().Len())
+	totals := map[string]int64{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		_, ok := rm.Resource().Attributes().Get(nodeIDKey)
+		must.False(t, ok)
+		cluster, ok := rm.Resource().Attributes().Get(clusterKey)
+		must.True(t, ok)
+
+		dps := rm.ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+		must.Eq(t, 1, dps.Len())
+		must.Eq(t, map[string]any{"envoy.response_code": "200"}, dps.At(0).Attributes().AsRaw())
+		totals[cluster.AsString()] = dps.At(0).IntValue()
+	}
+	must.Eq(t, map[string]int64{"api": 7 + 3, "web": 1}, totals)
+
+	// cumulative series keep contributing their last value until they are stale.
+	*now = now.Add(time.Minute)
+	must.NoError(t, a.export(ctx))
+	must.Eq(t, 2, sink.AllMetrics()[1].DataPointCount())
+
+	*now = now.Add(defaultMaxStaleness)
+	must.NoError(t, a.export(ctx))
+	must.Len(t, 2, sink.AllMetrics())
+	must.MapEmpty(t, a.series)
+}
+
+func TestAggregator_CounterStartResetOnSeriesChange(t *testing.T) {
+	a, sink, now := testAggregator(t, defaultConfig())
+	ctx := context.Background()
+	start := *now
+
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("api", "node-1", 5, *now)))
+	must.NoError(t, a.export(ctx))
+
+	*now = now.Add(time.Minute)
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("api", "node-1", 6, *now)))
+	must.NoError(t, a.export(ctx))
+
+	// a new series makes the total jump, so it starts over from the previous export.
+	*now = now.Add(time.Minute)
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("api", "node-1", 7, *now)))
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("api", "node-2", 100, *now)))
+	must.NoError(t, a.export(ctx))
+
+	*now = now.Add(time.Minute)
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("api", "node-1", 8, *now)))
+	must.NoError(t, a.ConsumeMetrics(ctx, counter("api", "node-2", 101, *now)))
+	must.NoError(t, a.export(ctx))
+
+	var starts []time.Time
+	var values []int64
+	for _, md := range sink.AllMetrics() {
+		dp := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0)
+		starts = append(starts, dp.StartTimestamp().AsTime())
+		values = append(values, dp.IntValue())
+	}
+	must.Eq(t, []int64{5, 6, 7 + 100, 8 + 101}, values)
+	reset := start.Add(time.Minute)
+	must.Eq(t, []time.Time{time.Unix(0, 0).UTC(), time.Unix(0, 0).UTC(), reset.UTC(), reset.UTC()}, starts)
+}
+
+func TestAggregator_Gauges(t *testing.T) {
+	for name, tc := range map[string]struct {
+		gaugeFunction string
+		expect        float64
+	}{
+		"Sum": {
+			gaugeFunction: GaugeFunctionSum,
+			expect:        2 + 7 + 4,
+		},
+		"Max": {
+			gaugeFunction: GaugeFunctionMax,
+			expect:        7,
+		},
+		"Last": {
+			gaugeFunction: GaugeFunctionLast,
+			expect:        4,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.GaugeFunction = tc.gaugeFunction
+			a, sink, now := testAggregator(t, cfg)
+			ctx := context.Background()
+
+			for i, value := range []float64{2, 7, 4} {
+				md := pmetric.NewMetrics()
+				rm := md.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr(clusterKey, "api")
+				rm.Resource().Attributes().PutStr(nodeIDKey, fmt.Sprintf("node-%d", i))
+				m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName("cluster.upstream_cx_active")
+				dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+				dp.SetDoubleValue(value)
+				dp.SetTimestamp(pcommon.NewTimestampFromTime(now.Add(time.Duration(i) * time.Second)))
+				must.NoError(t, a.ConsumeMetrics(ctx, md))
+			}
+
+			must.NoError(t, a.export(ctx))
+			must.Len(t, 1, sink.AllMetrics())
+			dps := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints()
+			must.Eq(t, 1, dps.Len())
+			must.Eq(t, tc.expect, dps.At(0).DoubleValue())
+		})
+	}
+}
+
+func TestAggregator_DeltaSums(t *testing.T) {
+	a, sink, now := testAggregator(t, defaultConfig())
+	ctx := context.Background()
+
+	for _, node := range []string{"node-1", "node-1", "node-2"} {
+		md := counter("api", node, 2, *now)
+		md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().
+			SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		must.NoError(t, a.ConsumeMetrics(ctx, md))
+	}
+
+	must.NoError(t, a.export(ctx))
+	must.Len(t, 1, sink.AllMetrics())
+	dps := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	must.Eq(t, 1, dps.Len())
+	must.Eq(t, 2+2+2, dps.At(0).IntValue())
+
+	// delta sums only count towards the interval they were reported in.
+	must.MapEmpty(t, a.series)
+}
+
+func TestAggregator_Histograms(t *testing.T) {
+	a, sink, now := testAggregator(t, defaultConfig())
+	ctx := context.Background()
+
+	for i, node := range []string{"node-1", "node-2"} {
+		md := pmetric.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr(clusterKey, "api")
+		rm.Resource().Attributes().PutStr(nodeIDKey, node)
+		m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("cluster.upstream_rq_time")
+		h := m.SetEmptyHistogram()
+		h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		dp := h.DataPoints().AppendEmpty()
+		dp.SetCount(uint64(i + 1))
+		dp.SetSum(float64(10 * (i + 1)))
+		dp.ExplicitBounds().FromRaw([]float64{1, 10})
+		dp.BucketCounts().FromRaw([]uint64{0, uint64(i + 1), 0})
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(*now))
+		must.NoError(t, a.ConsumeMetrics(ctx, md))
+	}
+
+	must.NoError(t, a.export(ctx))
+	must.Len(t, 1, sink.AllMetrics())
+	dps := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints()
+	must.Eq(t, 1, dps.Len())
+	must.Eq(t, 1+2, dps.At(0).Count())
+	must.Eq(t, 10+20, dps.At(0).Sum())
+	must.Eq(t, []uint64{0, 1 + 2, 0}, dps.At(0).BucketCounts().AsRaw())
+}
+
+func TestAggregator_PassthroughUnsupported(t *testing.T) {
+	a, sink, now := testAggregator(t, defaultConfig())
+	ctx := context.Background()
+
+	md := counter("api", "node-1", 1, *now)
+	summary := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().AppendEmpty()
+	summary.SetName("summary")
+	summary.SetEmptySummary().DataPoints().AppendEmpty().SetCount(1)
+
+	must.NoError(t, a.ConsumeMetrics(ctx, md))
+	must.Len(t, 1, sink.AllMetrics())
+	passed := sink.AllMetrics()[0]
+	must.Eq(t, 1, passed.MetricCount())
+	must.Eq(t, "summary", passed.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+	_, ok := passed.ResourceMetrics().At(0).Resource().Attributes().Get(nodeIDKey)
+	must.True(t, ok)
+
+	// the original metrics are left untouched.
+	must.Eq(t, 2, md.MetricCount())
+	must.MapLen(t, 1, a.series)
+}
@@ -0,0 +1,11 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cardinalityprocessor implements a processor that caps the number of unique series per metric name
+// and per proxy (the node.id resource attribute) over a rolling window.
+//
+// A series is a metric name, node.id and set of data point attributes. Series that have not been seen within
+// the window stop counting against the limits, within a tenth of the window since the series are expired at
+// most that often. Data points of new series beyond a limit are either dropped or
+// aggregated into a single series per metric carrying the otel_overflow attribute.
+package cardinalityprocessor
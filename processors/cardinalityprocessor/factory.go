@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cardinalityprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// ID is the identifier for the processor.
+	ID = "cardinality_limiter"
+
+	// OverflowActionDrop drops the data points of series beyond a limit.
+	OverflowActionDrop = "drop"
+
+	// OverflowActionAggregate merges the data points of series beyond a limit into a single overflow series
+	// per metric.
+	OverflowActionAggregate = "aggregate"
+
+	defaultMaxSeriesPerMetric = 1000
+	defaultMaxSeriesPerNode   = 10000
+	defaultWindow             = 10 * time.Minute
+)
+
+// Config is the configuration for the cardinality limiter processor.
+type Config struct {
+	// MaxSeriesPerMetric is the number of unique series allowed per metric name. 0 disables the limit.
+	MaxSeriesPerMetric int `mapstructure:"max_series_per_metric"`
+
+	// MaxSeriesPerNode is the number of unique series allowed per node.id. 0 disables the limit.
+	MaxSeriesPerNode int `mapstructure:"max_series_per_node"`
+
+	// Window is how long a series counts against the limits after its last data point.
+	Window time.Duration `mapstructure:"window"`
+
+	// OverflowAction is what happens to the data points of series beyond a limit: drop or aggregate.
+	OverflowAction string `mapstructure:"overflow_action"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that the configuration is usable.
+func (c *Config) Validate() error {
+	if c.MaxSeriesPerMetric < 0 || c.MaxSeriesPerNode < 0 {
+		return errors.New("series limits must not be negative")
+	}
+	if c.Window <= 0 {
+		return errors.New("window must be positive")
+	}
+	switch c.OverflowAction {
+	case OverflowActionDrop, OverflowActionAggregate:
+		return nil
+	default:
+		return fmt.Errorf("overflow_action %q must be %s or %s", c.OverflowAction, OverflowActionDrop,
+			OverflowActionAggregate)
+	}
+}
+
+// NewFactory creates a new cardinality limiter processor factory.
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		ID,
+		CreateDefaultConfig,
+		processor.WithMetrics(createMetrics, component.StabilityLevelDevelopment),
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for the processor.
+func CreateDefaultConfig() component.Config {
+	return &Config{
+		MaxSeriesPerMetric: defaultMaxSeriesPerMetric,
+		MaxSeriesPerNode:   defaultMaxSeriesPerNode,
+		Window:             defaultWindow,
+		OverflowAction:     OverflowActionAggregate,
+	}
+}
+
+func createMetrics(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (processor.Metrics, error) {
+	limiter, err := newLimiter(cfg.(*Config), set)
+	if err != nil {
+		return nil, err
+	}
+
+	return processorhelper.NewMetricsProcessor(
+		ctx,
+		set,
+		cfg,
+		nextConsumer,
+		limiter.processMetrics,
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
+	)
+}
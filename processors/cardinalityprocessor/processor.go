@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cardinalityprocessor
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/zap"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/datapoint"
+)
+
+const (
+	// OverflowKey is the attribute set on the series data points beyond a limit are aggregated into.
+	OverflowKey = "otel_overflow"
+
+	// nodeIDKey is the resource attribute identifying the proxy that reported a metric.
+	nodeIDKey = "node.id"
+
+	// expireDivisor is the fraction of the window between two walks of the tracked series to expire them. A
+	// series may be tracked for up to a window and a tenth after its last data point.
+	expireDivisor = 10
+)
+
+// limit identifies the limit a series exceeded.
+type limit string
+
+const (
+	limitNone   limit = ""
+	limitMetric limit = "metric"
+	limitNode   limit = "node"
+)
+
+// limiter tracks the series seen within the window and decides which data points are admitted.
+type limiter struct {
+	cfg       *Config
+	logger    *zap.Logger
+	telemetry *limiterTelemetry
+	now       func() time.Time
+
+	mu sync.Mutex
+	// metricSeries and nodeSeries map a metric name and node.id to the series they reported and when each
+	// series was last seen. A series is always tracked in both.
+	metricSeries map[string]map[string]time.Time
+	nodeSeries   map[string]map[string]time.Time
+	// lastExpired is when the tracked series were last walked to expire them.
+	lastExpired time.Time
+}
+
+func newLimiter(cfg *Config, set processor.CreateSettings) (*limiter, error) {
+	telemetry, err := newLimiterTelemetry(set.TelemetrySettings.MeterProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &limiter{
+		cfg:          cfg,
+		logger:       set.Logger,
+		telemetry:    telemetry,
+		now:          time.Now,
+		metricSeries: make(map[string]map[string]time.Time),
+		nodeSeries:   make(map[string]map[string]time.Time),
+	}, nil
+}
+
+func (l *limiter) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.expire(now)
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		nodeID := ""
+		if v, ok := rm.Resource().Attributes().Get(nodeIDKey); ok {
+			nodeID = v.AsString()
+		}
+
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sms.At(j).Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				l.limitMetric(ctx, now, nodeID, m)
+				return datapoint.Count(m) == 0
+			})
+		}
+	}
+
+	return md, nil
+}
+
+// limitMetric removes the data points of the metric that exceed a limit, aggregating them into an overflow
+// data point when configured to.
+func (l *limiter) limitMetric(ctx context.Context, now time.Time, nodeID string, m pmetric.Metric) {
+	aggregate := l.cfg.OverflowAction == OverflowActionAggregate
+
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		limitNumberDataPoints(m.Sum().DataPoints(), aggregate, l.admitter(ctx, now, nodeID, m.Name()), datapoint.MergeSum)
+	case pmetric.MetricTypeGauge:
+		limitNumberDataPoints(m.Gauge().DataPoints(), aggregate, l.admitter(ctx, now, nodeID, m.Name()), datapoint.MergeGauge)
+	case pmetric.MetricTypeHistogram:
+		limitHistogramDataPoints(m.Histogram().DataPoints(), aggregate, l.admitter(ctx, now, nodeID, m.Name()))
+	case pmetric.MetricTypeExponentialHistogram:
+		// exponential histograms and summaries can't be merged so their overflow is always dropped.
+		admit := l.admitter(ctx, now, nodeID, m.Name())
+		m.ExponentialHistogram().DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+			return !admit(dp.Attributes(), false)
+		})
+	case pmetric.MetricTypeSummary:
+		admit := l.admitter(ctx, now, nodeID, m.Name())
+		m.Summary().DataPoints().RemoveIf(func(dp pmetric.SummaryDataPoint) bool {
+			return !admit(dp.Attributes(), false)
+		})
+	case pmetric.MetricTypeEmpty:
+	}
+}
+
+// admitFunc reports whether a data point with the attributes is admitted. aggregated is true if a data point
+// that is not admitted is aggregated rather than dropped.
+type admitFunc func(attrs pcommon.Map, aggregated bool) bool
+
+func (l *limiter) admitter(ctx context.Context, now time.Time, nodeID, name string) admitFunc {
+	return func(attrs pcommon.Map, aggregated bool) bool {
+		exceeded := l.admit(now, name, nodeID, seriesKey(nodeID, attrs))
+		if exceeded == limitNone {
+			return true
+		}
+		if aggregated {
+			l.telemetry.overflowed(ctx, name, exceeded)
+		} else {
+			l.telemetry.dropped(ctx, name, exceeded)
+		}
+		return false
+	}
+}
+
+// admit tracks the series and returns the limit it exceeds, if any. Series already tracked are always admitted.
+func (l *limiter) admit(now time.Time, name, nodeID, key string) limit {
+	if _, ok := l.metricSeries[name][key]; ok {
+		l.metricSeries[name][key] = now
+		l.nodeSeries[nodeID][name+key] = now
+		return limitNone
+	}
+
+	if l.cfg.MaxSeriesPerMetric > 0 && len(l.metricSeries[name]) >= l.cfg.MaxSeriesPerMetric {
+		return limitMetric
+	}
+	if l.cfg.MaxSeriesPerNode > 0 && len(l.nodeSeries[nodeID]) >= l.cfg.MaxSeriesPerNode {
+		return limitNode
+	}
+
+	if l.metricSeries[name] == nil {
+		l.metricSeries[name] = make(map[string]time.Time)
+	}
+	if l.nodeSeries[nodeID] == nil {
+		l.nodeSeries[nodeID] = make(map[string]time.Time)
+	}
+	l.metricSeries[name][key] = now
+	l.nodeSeries[nodeID][name+key] = now
+	return limitNone
+}
+
+// expire stops tracking the series that have not been seen within the window. Walking every series is only done
+// once per tenth of the window, rather than for every batch.
+func (l *limiter) expire(now time.Time) {
+	if now.Sub(l.lastExpired) < l.cfg.Window/expireDivisor {
+		return
+	}
+	l.lastExpired = now
+
+	cutoff := now.Add(-l.cfg.Window)
+	for _, tracked := range []map[string]map[string]time.Time{l.metricSeries, l.nodeSeries} {
+		for owner, series := range tracked {
+			for key, lastSeen := range series {
+				if lastSeen.Before(cutoff) {
+					delete(series, key)
+				}
+			}
+			if len(series) == 0 {
+				delete(tracked, owner)
+			}
+		}
+	}
+}
+
+// seriesKey identifies a series of a metric by the node that reported it and its data point attributes.
+func seriesKey(nodeID string, attrs pcommon.Map) string {
+	pairs := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		pairs = append(pairs, k+"="+v.AsString())
+		return true
+	})
+	sort.Strings(pairs)
+
+	return "\x00" + nodeID + "\x00" + strings.Join(pairs, "\x00")
+}
+
+func limitNumberDataPoints(
+	dps pmetric.NumberDataPointSlice,
+	aggregate bool,
+	admit admitFunc,
+	merge func(into, from pmetric.NumberDataPoint),
+) {
+	var overflow pmetric.NumberDataPoint
+	hasOverflow := false
+	dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+		if admit(dp.Attributes(), aggregate) {
+			return false
+		}
+		if !aggregate {
+			return true
+		}
+		if !hasOverflow {
+			overflow = pmetric.NewNumberDataPoint()
+			dp.CopyTo(overflow)
+			setOverflowAttributes(overflow.Attributes())
+			hasOverflow = true
+		} else {
+			merge(overflow, dp)
+		}
+		return true
+	})
+
+	if hasOverflow {
+		overflow.MoveTo(dps.AppendEmpty())
+	}
+}
+
+func limitHistogramDataPoints(dps pmetric.HistogramDataPointSlice, aggregate bool, admit admitFunc) {
+	var overflow pmetric.HistogramDataPoint
+	hasOverflow := false
+	dps.RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+		if admit(dp.Attributes(), aggregate) {
+			return false
+		}
+		if !aggregate {
+			return true
+		}
+		if !hasOverflow {
+			overflow = pmetric.NewHistogramDataPoint()
+			dp.CopyTo(overflow)
+			setOverflowAttributes(overflow.Attributes())
+			hasOverflow = true
+		} else {
+			datapoint.MergeHistogram(overflow, dp)
+		}
+		return true
+	})
+
+	if hasOverflow {
+		overflow.MoveTo(dps.AppendEmpty())
+	}
+}
+
+func setOverflowAttributes(attrs pcommon.Map) {
+	attrs.Clear()
+	attrs.PutBool(OverflowKey, true)
+}
@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cardinalityprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestLimiter_MaxSeriesPerMetric(t *testing.T) {
+	l, reader := testLimiter(t, &Config{MaxSeriesPerMetric: 2, Window: time.Minute, OverflowAction: OverflowActionDrop})
+
+	md := sums("node-1", "requests", 3)
+	md, err := l.processMetrics(context.Background(), md)
+	must.NoError(t, err)
+	must.Eq(t, 2, md.DataPointCount())
+
+	// already tracked series are still admitted
+	md, err = l.processMetrics(context.Background(), sums("node-1", "requests", 2))
+	must.NoError(t, err)
+	must.Eq(t, 2, md.DataPointCount())
+
+	// other metrics have their own limit
+	md, err = l.processMetrics(context.Background(), sums("node-1", "responses", 2))
+	must.NoError(t, err)
+	must.Eq(t, 2, md.DataPointCount())
+
+	must.Eq(t, 1, counterValue(t, reader, "cardinality_limiter_dropped_data_points",
+		attribute.String(metricNameKey, "requests"), attribute.String(limitKey, string(limitMetric))))
+}
+
+func TestLimiter_MaxSeriesPerNode(t *testing.T) {
+	l, reader := testLimiter(t, &Config{MaxSeriesPerNode: 3, Window: time.Minute, OverflowAction: OverflowActionDrop})
+
+	md, err := l.processMetrics(context.Background(), sums("node-1", "requests", 2))
+	must.NoError(t, err)
+	must.Eq(t, 2, md.DataPointCount())
+
+	md, err = l.processMetrics(context.Background(), sums("node-1", "responses", 2))
+	must.NoError(t, err)
+	must.Eq(t, 1, md.DataPointCount())
+
+	// other nodes have their own limit
+	md, err = l.processMetrics(context.Background(), sums("node-2", "responses", 2))
+	must.NoError(t, err)
+	must.Eq(t, 2, md.DataPointCount())
+
+	must.Eq(t, 1, counterValue(t, reader, "cardinality_limiter_dropped_data_points",
+		attribute.String(metricNameKey, "responses"), attribute.String(limitKey, string(limitNode))))
+}
+
+func TestLimiter_DropRemovesEmptyMetrics(t *testing.T) {
+	l, _ := testLimiter(t, &Config{MaxSeriesPerNode: 1, Window: time.Minute, OverflowAction: OverflowActionDrop})
+
+	md, err := l.processMetrics(context.Background(), sums("node-1", "requests", 1))
+	must.NoError(t, err)
+	must.Eq(t, 1, md.MetricCount())
+
+	md, err = l.processMetrics(context.Background(), sums("node-1", "responses", 1))
+	must.NoError(t, err)
+	must.Eq(t, 0, md.MetricCount())
+}
+
+func TestLimiter_Window(t *testing.T) {
+	l, _ := testLimiter(t, &Config{MaxSeriesPerMetric: 1, Window: time.Minute, OverflowAction: OverflowActionDrop})
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	md, err := l.processMetrics(context.Background(), sums("node-1", "requests", 1))
+	must.NoError(t, err)
+	must.Eq(t, 1, md.DataPointCount())
+
+	md, err = l.processMetrics(context.Background(), sums("node-2", "requests", 1))
+	must.NoError(t, err)
+	must.Eq(t, 0, md.DataPointCount())
+
+	// node-1's series expires and stops counting against the limit
+	now = now.Add(2 * time.Minute)
+	md, err = l.processMetrics(context.Background(), sums("node-2", "requests", 1))
+	must.NoError(t, err)
+	must.Eq(t, 1, md.DataPointCount())
+	must.MapLen(t, 1, l.nodeSeries)
+}
+
+func TestLimiter_ExpireOncePerTenthOfWindow(t *testing.T) {
+	l, _ := testLimiter(t, &Config{MaxSeriesPerMetric: 1, Window: time.Minute, OverflowAction: OverflowActionDrop})
+	start := time.Now()
+	now := start
+	l.now = func() time.Time { return now }
+
+	_, err := l.processMetrics(context.Background(), sums("node-1", "requests", 1))
+	must.NoError(t, err)
+
+	// the series is walked just before it expires, and not walked again until a tenth of the window has passed.
+	now = start.Add(59 * time.Second)
+	_, err = l.processMetrics(context.Background(), pmetric.NewMetrics())
+	must.NoError(t, err)
+	now = start.Add(62 * time.Second)
+	_, err = l.processMetrics(context.Background(), pmetric.NewMetrics())
+	must.NoError(t, err)
+	must.MapLen(t, 1, l.metricSeries)
+
+	now = start.Add(65 * time.Second)
+	_, err = l.processMetrics(context.Background(), pmetric.NewMetrics())
+	must.NoError(t, err)
+	must.MapEmpty(t, l.metricSeries)
+	must.MapEmpty(t, l.nodeSeries)
+}
+
+func TestLimiter_AggregateSums(t *testing.T) {
+	l, reader := testLimiter(t, &Config{MaxSeriesPerMetric: 1, Window: time.Minute, OverflowAction: OverflowActionAggregate})
+
+	md, err := l.processMetrics(context.Background(), sums("node-1", "requests", 4))
+	must.NoError(t, err)
+
+	dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	must.Eq(t, 2, dps.Len())
+	must.Eq(t, 0, dps.At(0).IntValue())
+
+	overflow := dps.At(1)
+	must.Eq(t, 1+2+3, overflow.IntValue())
+	must.Eq(t, map[string]any{OverflowKey: true}, overflow.Attributes().AsRaw())
+
+	must.Eq(t, 3, counterValue(t, reader, "cardinality_limiter_overflow_data_points",
+		attribute.String(metricNameKey, "requests"), attribute.String(limitKey, string(limitMetric))))
+}
+
+func TestLimiter_AggregateHistograms(t *testing.T) {
+	l, _ := testLimiter(t, &Config{MaxSeriesPerMetric: 1, Window: time.Minute, OverflowAction: OverflowActionAggregate})
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr(nodeIDKey, "node-1")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("latency")
+	dps := m.SetEmptyHistogram().DataPoints()
+	for i := 0; i < 3; i++ {
+		dp := dps.AppendEmpty()
+		dp.Attributes().PutStr("route", fmt.Sprintf("/%d", i))
+		dp.SetCount(uint64(i + 1))
+		dp.SetSum(float64(i + 1))
+		dp.ExplicitBounds().FromRaw([]float64{1, 10})
+		dp.BucketCounts().FromRaw([]uint64{uint64(i), 1, 0})
+	}
+
+	md, err := l.processMetrics(context.Background(), md)
+	must.NoError(t, err)
+
+	dps = md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints()
+	must.Eq(t, 2, dps.Len())
+	overflow := dps.At(1)
+	must.Eq(t, 2+3, overflow.Count())
+	must.Eq(t, 2+3, overflow.Sum())
+	must.Eq(t, []uint64{1 + 2, 2, 0}, overflow.BucketCounts().AsRaw())
+	must.Eq(t, []float64{1, 10}, overflow.ExplicitBounds().AsRaw())
+}
+
+func Test_seriesKey(t *testing.T) {
+	a := pcommon.NewMap()
+	a.PutStr("x", "1")
+	a.PutStr("y", "2")
+	b := pcommon.NewMap()
+	b.PutStr("y", "2")
+	b.PutStr("x", "1")
+
+	must.Eq(t, seriesKey("node", a), seriesKey("node", b))
+	must.NotEq(t, seriesKey("node", a), seriesKey("other", a))
+}
+
+func testLimiter(t *testing.T, cfg *Config) (*limiter, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	set := processortest.NewNopCreateSettings()
+	set.TelemetrySettings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	l, err := newLimiter(cfg, set)
+	must.NoError(t, err)
+	return l, reader
+}
+
+// sums creates a sum metric reported by the node with a data point per series. The value of each data point is
+// its series index.
+func sums(nodeID, name string, series int) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr(nodeIDKey, nodeID)
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	dps := m.SetEmptySum().DataPoints()
+	for i := 0; i < series; i++ {
+		dp := dps.AppendEmpty()
+		dp.Attributes().PutStr("route", fmt.Sprintf("/%d", i))
+		dp.SetIntValue(int64(i))
+	}
+	return md
+}
+
+func counterValue(t *testing.T, reader sdkmetric.Reader, name string, attrs ...attribute.KeyValue) int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	must.NoError(t, reader.Collect(context.Background(), &rm))
+
+	want := attribute.NewSet(attrs...)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if dp.Attributes.Equals(&want) {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cardinalityprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
+)
+
+const (
+	// scopeName is the instrumentation scope of the processor's own metrics.
+	scopeName = "github.com/hashicorp/consul-telemetry-collector/processors/cardinalityprocessor"
+
+	metricNameKey = "metric_name"
+	limitKey      = "limit"
+)
+
+// limiterTelemetry records the data points of series that exceeded a limit.
+type limiterTelemetry struct {
+	droppedDataPoints    metric.Int64Counter
+	overflowedDataPoints metric.Int64Counter
+}
+
+func newLimiterTelemetry(meterProvider metric.MeterProvider) (*limiterTelemetry, error) {
+	meter := meterProvider.Meter(scopeName)
+
+	var errs, err error
+	t := &limiterTelemetry{}
+
+	t.droppedDataPoints, err = meter.Int64Counter("cardinality_limiter_dropped_data_points",
+		metric.WithDescription("Number of data points dropped because their series exceeded a cardinality limit"))
+	errs = multierr.Append(errs, err)
+
+	t.overflowedDataPoints, err = meter.Int64Counter("cardinality_limiter_overflow_data_points",
+		metric.WithDescription("Number of data points aggregated into the overflow series because their series exceeded a cardinality limit"))
+	errs = multierr.Append(errs, err)
+
+	return t, errs
+}
+
+func (t *limiterTelemetry) dropped(ctx context.Context, name string, exceeded limit) {
+	t.droppedDataPoints.Add(ctx, 1, attributes(name, exceeded))
+}
+
+func (t *limiterTelemetry) overflowed(ctx context.Context, name string, exceeded limit) {
+	t.overflowedDataPoints.Add(ctx, 1, attributes(name, exceeded))
+}
+
+func attributes(name string, exceeded limit) metric.AddOption {
+	return metric.WithAttributes(
+		attribute.String(metricNameKey, name),
+		attribute.String(limitKey, string(exceeded)),
+	)
+}
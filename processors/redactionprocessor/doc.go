@@ -0,0 +1,11 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package redactionprocessor implements a processor that redacts sensitive resource and data point attribute
+// values, like the hostnames, pod names or tenant identifiers envoy stats and node IDs can carry, before they are
+// exported.
+//
+// Every selected attribute is either dropped, masked by replacing its value with a fixed string, or replaced by
+// the hex encoded HMAC-SHA256 of its value. Hashing keeps the series of distinct values apart without exporting
+// the values themselves, and the secret key keeps the hashes from being reversed by hashing candidate values.
+package redactionprocessor
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package redactionprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// ID is the identifier for the processor.
+	ID = "redaction"
+
+	// ActionDrop removes the attribute.
+	ActionDrop = "drop"
+
+	// ActionMask replaces the value of the attribute with MaskedValue.
+	ActionMask = "mask"
+
+	// ActionHash replaces the value of the attribute with the hex encoded HMAC-SHA256 of its value, keyed with the
+	// HashKey.
+	ActionHash = "hash"
+
+	// MaskedValue is the value of masked attributes.
+	MaskedValue = "***"
+)
+
+// Config is the configuration for the redaction processor.
+type Config struct {
+	// ResourceAttributes are the redactions of the resource attributes.
+	ResourceAttributes []Redaction `mapstructure:"resource_attributes,omitempty"`
+
+	// Attributes are the redactions of the data point attributes.
+	Attributes []Redaction `mapstructure:"attributes,omitempty"`
+
+	// HashKey is the secret key of the hash action. It is required to hash attributes, a plain hash of the values
+	// could be reversed by hashing the likely ones, like the pod names of a deployment.
+	HashKey configopaque.String `mapstructure:"hash_key,omitempty"`
+}
+
+// Redaction is the action applied to the value of an attribute.
+type Redaction struct {
+	// Key is the name of the attribute.
+	Key string `mapstructure:"key"`
+
+	// Action is what happens to the attribute: drop, mask or hash.
+	Action string `mapstructure:"action"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks that every redaction has a key and a known action, and that there is a key to hash with.
+func (c *Config) Validate() error {
+	for _, redactions := range [][]Redaction{c.ResourceAttributes, c.Attributes} {
+		for _, r := range redactions {
+			if r.Key == "" {
+				return errors.New("redactions must have a key")
+			}
+			switch r.Action {
+			case ActionDrop, ActionMask:
+			case ActionHash:
+				if c.HashKey == "" {
+					return fmt.Errorf("hash_key must be set to hash attribute %q", r.Key)
+				}
+			default:
+				return fmt.Errorf("action %q of attribute %q must be %s, %s or %s", r.Action, r.Key, ActionDrop,
+					ActionMask, ActionHash)
+			}
+		}
+	}
+	return nil
+}
+
+// NewFactory creates a new redaction processor factory.
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		ID,
+		CreateDefaultConfig,
+		processor.WithMetrics(createMetrics, component.StabilityLevelDevelopment),
+	)
+}
+
+// CreateDefaultConfig creates the default configuration for the processor, which redacts nothing.
+func CreateDefaultConfig() component.Config {
+	return &Config{}
+}
+
+func createMetrics(
+	ctx context.Context,
+	set processor.CreateSettings,
+	cfg component.Config,
+	nextConsumer consumer.Metrics,
+) (processor.Metrics, error) {
+	r := newRedactor(cfg.(*Config))
+
+	return processorhelper.NewMetricsProcessor(
+		ctx,
+		set,
+		cfg,
+		nextConsumer,
+		r.processMetrics,
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
+	)
+}
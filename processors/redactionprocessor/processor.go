@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package redactionprocessor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// redactor applies the redactions of the configuration to the attributes of the metrics.
+type redactor struct {
+	resourceAttributes []Redaction
+	attributes         []Redaction
+	hashKey            []byte
+}
+
+func newRedactor(cfg *Config) *redactor {
+	return &redactor{
+		resourceAttributes: cfg.ResourceAttributes,
+		attributes:         cfg.Attributes,
+		hashKey:            []byte(cfg.HashKey),
+	}
+}
+
+func (r *redactor) processMetrics(_ context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		r.redact(rm.Resource().Attributes(), r.resourceAttributes)
+
+		if len(r.attributes) == 0 {
+			continue
+		}
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				r.redactDataPoints(metrics.At(k))
+			}
+		}
+	}
+	return md, nil
+}
+
+// redactDataPoints redacts the attributes of every data point of the metric.
+func (r *redactor) redactDataPoints(m pmetric.Metric) {
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			r.redact(dps.At(i).Attributes(), r.attributes)
+		}
+	case pmetric.MetricTypeSum:
+		dps := m.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			r.redact(dps.At(i).Attributes(), r.attributes)
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			r.redact(dps.At(i).Attributes(), r.attributes)
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			r.redact(dps.At(i).Attributes(), r.attributes)
+		}
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			r.redact(dps.At(i).Attributes(), r.attributes)
+		}
+	}
+}
+
+// redact applies the redactions to the attributes that are set.
+func (r *redactor) redact(attrs pcommon.Map, redactions []Redaction) {
+	for _, redaction := range redactions {
+		v, ok := attrs.Get(redaction.Key)
+		if !ok {
+			continue
+		}
+		switch redaction.Action {
+		case ActionDrop:
+			attrs.Remove(redaction.Key)
+		case ActionMask:
+			v.SetStr(MaskedValue)
+		case ActionHash:
+			v.SetStr(r.hash(v.AsString()))
+		}
+	}
+}
+
+// hash returns the hex encoded HMAC-SHA256 of the value.
+func (r *redactor) hash(value string) string {
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
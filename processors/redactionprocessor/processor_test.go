@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package redactionprocessor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/shoenig/test/must"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const testHashKey = "test-hash-key"
+
+func hmacHex(key, s string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor(&Config{
+		ResourceAttributes: []Redaction{
+			{Key: "node.id", Action: ActionHash},
+			{Key: "host.name", Action: ActionDrop},
+			{Key: "missing", Action: ActionMask},
+		},
+		Attributes: []Redaction{
+			{Key: "pod", Action: ActionDrop},
+			{Key: "tenant", Action: ActionMask},
+			{Key: "port", Action: ActionHash},
+		},
+		HashKey: testHashKey,
+	})
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("node.id", "web-5d8f7-abcde")
+	rm.Resource().Attributes().PutStr("host.name", "ip-10-0-0-1")
+	rm.Resource().Attributes().PutStr("envoy.cluster", "web")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("server.live")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.Attributes().PutStr("pod", "web-5d8f7-abcde")
+	dp.Attributes().PutStr("tenant", "acme")
+	dp.Attributes().PutInt("port", 8080)
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("cluster.upstream_rq_time")
+	hdp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.Attributes().PutStr("tenant", "acme")
+	hdp.Attributes().PutStr("envoy.cluster_name", "api")
+
+	md, err := r.processMetrics(context.Background(), md)
+	must.NoError(t, err)
+
+	must.Eq(t, map[string]any{
+		"node.id":       hmacHex(testHashKey, "web-5d8f7-abcde"),
+		"envoy.cluster": "web",
+	}, md.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+
+	metrics = md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	must.Eq(t, map[string]any{
+		"tenant": MaskedValue,
+		"port":   hmacHex(testHashKey, "8080"),
+	}, metrics.At(0).Gauge().DataPoints().At(0).Attributes().AsRaw())
+	must.Eq(t, map[string]any{
+		"tenant":             MaskedValue,
+		"envoy.cluster_name": "api",
+	}, metrics.At(1).Histogram().DataPoints().At(0).Attributes().AsRaw())
+}
+
+func TestRedactor_HashIsKeyed(t *testing.T) {
+	hashed := func(key, nodeID string) string {
+		r := newRedactor(&Config{
+			ResourceAttributes: []Redaction{{Key: "node.id", Action: ActionHash}},
+			HashKey:            configopaque.String(key),
+		})
+		md := pmetric.NewMetrics()
+		md.ResourceMetrics().AppendEmpty().Resource().Attributes().PutStr("node.id", nodeID)
+		md, err := r.processMetrics(context.Background(), md)
+		must.NoError(t, err)
+		v, ok := md.ResourceMetrics().At(0).Resource().Attributes().Get("node.id")
+		must.True(t, ok)
+		must.Eq(t, pcommon.ValueTypeStr, v.Type())
+		return v.Str()
+	}
+
+	must.Eq(t, hashed("key-1", "node-1"), hashed("key-1", "node-1"))
+	must.NotEq(t, hashed("key-1", "node-1"), hashed("key-1", "node-2"))
+
+	// the same value hashes differently with another key, and not to its plain SHA-256.
+	must.NotEq(t, hashed("key-1", "node-1"), hashed("key-2", "node-1"))
+	sum := sha256.Sum256([]byte("node-1"))
+	must.NotEq(t, hex.EncodeToString(sum[:]), hashed("key-1", "node-1"))
+}
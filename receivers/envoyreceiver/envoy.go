@@ -14,6 +14,7 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"github.com/hashicorp/consul-telemetry-collector/internal/translator/otlp/prometheus"
 	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver/metrics"
 )
 
@@ -35,6 +36,23 @@ var _ component.Config = (*Config)(nil)
 // Config is the configuration for the envoy receiver.
 type Config struct {
 	GRPC *configgrpc.GRPCServerSettings `mapstructure:"grpc"`
+	// TagExtraction moves the dimensions envoy embeds in stat names into data point attributes when set.
+	TagExtraction *TagExtractionConfig `mapstructure:"tag_extraction"`
+	// StripUnitSuffixes removes the unit suffixes, like _bytes or _ms, and the _total suffix of counters from the
+	// metric names. The names are kept unchanged by default.
+	StripUnitSuffixes bool `mapstructure:"strip_unit_suffixes"`
+	// ResourceAttributes selects the envoy node properties set as resource attributes. The namespace and partition
+	// node metadata are set by default.
+	ResourceAttributes ResourceAttributesConfig `mapstructure:"resource_attributes"`
+}
+
+// Validate checks the receiver configuration is valid.
+func (c *Config) Validate() error {
+	if c.TagExtraction == nil {
+		return nil
+	}
+	_, err := c.TagExtraction.extractors()
+	return err
 }
 
 func newEnvoyReceiver(
@@ -100,6 +118,24 @@ func (r *envoyReceiver) Shutdown(_ context.Context) error {
 	return nil
 }
 
-func (r *envoyReceiver) registerMetrics(nextConsumer consumer.Metrics) {
-	r.metricsReceiver = metrics.New(nextConsumer, r.logger)
+func (r *envoyReceiver) registerMetrics(nextConsumer consumer.Metrics) error {
+	var builderOpts []prometheus.Option
+	if r.cfg.TagExtraction != nil {
+		extractors, err := r.cfg.TagExtraction.extractors()
+		if err != nil {
+			return err
+		}
+		builderOpts = append(builderOpts, prometheus.WithTagExtraction(extractors...))
+	}
+	if r.cfg.StripUnitSuffixes {
+		builderOpts = append(builderOpts, prometheus.WithUnitSuffixStripping())
+	}
+
+	metricsReceiver, err := metrics.New(nextConsumer, r.logger, r.settings.TelemetrySettings.MeterProvider,
+		r.cfg.ResourceAttributes.attributes(), builderOpts...)
+	if err != nil {
+		return err
+	}
+	r.metricsReceiver = metricsReceiver
+	return nil
 }
@@ -40,3 +40,84 @@ func TestUnmarshalConfigUnix(t *testing.T) {
 	}
 	test.Eq(t, actualCfg, marshalCfg)
 }
+
+func TestUnmarshalConfigTagExtraction(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "tag_extraction.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	test.NoError(t, component.UnmarshalConfig(cm, cfg))
+	test.NoError(t, component.ValidateConfig(cfg))
+
+	expectedCfg := factory.CreateDefaultConfig().(*Config)
+	expectedCfg.StripUnitSuffixes = true
+	expectedCfg.TagExtraction = &TagExtractionConfig{
+		Extractors: []TagExtractorConfig{
+			{
+				Name:  "envoy.route",
+				Regex: `^vhost\.(?:.*?\.)?route\.((.+?)\.)`,
+			},
+		},
+	}
+	test.Eq(t, expectedCfg, cfg.(*Config))
+}
+
+func TestValidateTagExtraction(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.TagExtraction = &TagExtractionConfig{
+		Extractors: []TagExtractorConfig{
+			{
+				Name:  "envoy.route",
+				Regex: `^vhost\.`,
+			},
+		},
+	}
+	test.ErrorContains(t, component.ValidateConfig(cfg), "must have a capture group")
+}
+
+func TestUnmarshalConfigResourceAttributes(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "resource_attributes.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	test.NoError(t, component.UnmarshalConfig(cm, cfg))
+	test.NoError(t, component.ValidateConfig(cfg))
+
+	expectedCfg := factory.CreateDefaultConfig().(*Config)
+	expectedCfg.ResourceAttributes = ResourceAttributesConfig{
+		NodeMetadata: map[string]string{
+			"namespace":  "consul.namespace",
+			"partition":  "partition",
+			"datacenter": "consul.datacenter",
+		},
+		Locality: map[string]string{
+			"region": "cloud.region",
+			"zone":   "cloud.availability_zone",
+		},
+		BuildVersion: "envoy.version",
+	}
+	test.Eq(t, expectedCfg, cfg.(*Config))
+}
+
+func TestValidateResourceAttributes(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.ResourceAttributes.Locality = map[string]string{"country": "geo.country"}
+	test.ErrorContains(t, component.ValidateConfig(cfg), `unknown locality field "country"`)
+
+	cfg = NewFactory().CreateDefaultConfig().(*Config)
+	cfg.ResourceAttributes.NodeMetadata["pod"] = "node.id"
+	test.ErrorContains(t, component.ValidateConfig(cfg), `node_metadata "pod" can't be set to the reserved attribute "node.id"`)
+
+	cfg = NewFactory().CreateDefaultConfig().(*Config)
+	cfg.ResourceAttributes.Locality = map[string]string{"zone": "envoy.cluster"}
+	test.ErrorContains(t, component.ValidateConfig(cfg), `reserved attribute "envoy.cluster"`)
+
+	// an empty name keeps the property name, which can be reserved too.
+	cfg = NewFactory().CreateDefaultConfig().(*Config)
+	cfg.ResourceAttributes.NodeMetadata["node.id"] = ""
+	test.ErrorContains(t, component.ValidateConfig(cfg), `reserved attribute "node.id"`)
+
+	cfg = NewFactory().CreateDefaultConfig().(*Config)
+	cfg.ResourceAttributes.BuildVersion = "node.id"
+	test.ErrorContains(t, component.ValidateConfig(cfg), `build_version can't be set to the reserved attribute`)
+}
@@ -46,7 +46,9 @@ func createMetrics(_ context.Context,
 	envoyCfg := cfg.(*Config)
 	envoy := newEnvoyReceiver(set, envoyCfg)
 
-	envoy.registerMetrics(nextConsumer)
+	if err := envoy.registerMetrics(nextConsumer); err != nil {
+		return nil, err
+	}
 
 	return receiver.Metrics(envoy), nil
 }
@@ -72,5 +74,8 @@ func CreateDefaultConfig() component.Config {
 				},
 			},
 		},
+		ResourceAttributes: ResourceAttributesConfig{
+			NodeMetadata: withDefaultNames(defaultNodeMetadata),
+		},
 	}
 }
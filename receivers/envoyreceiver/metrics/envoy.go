@@ -11,6 +11,7 @@ import (
 	prompb "github.com/prometheus/client_model/go"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
@@ -21,20 +22,36 @@ import (
 type Receiver struct {
 	nextConsumer consumer.Metrics
 	logger       *zap.Logger
+	telemetry    *receiverTelemetry
+	attributes   ResourceAttributes
+	builderOpts  []prometheus.Option
 }
 
 var _ metricsv3.MetricsServiceServer = (*Receiver)(nil)
 
-const namespaceKey = "namespace"
-const partitionKey = "partition"
+// New creates a new Receiver reference. The receiver records metrics about the streams it serves with
+// the meterProvider. The attributes select the node properties set as resource attributes and the builderOpts
+// configure how the envoy metrics are translated to OTLP.
+func New(
+	nextConsumer consumer.Metrics,
+	logger *zap.Logger,
+	meterProvider metric.MeterProvider,
+	attributes ResourceAttributes,
+	builderOpts ...prometheus.Option,
+) (*Receiver, error) {
+	telemetry, err := newReceiverTelemetry(meterProvider)
+	if err != nil {
+		return nil, err
+	}
 
-// New creates a new Receiver reference.
-func New(nextConsumer consumer.Metrics, logger *zap.Logger) *Receiver {
 	logger.Info("Created new receiver")
 	return &Receiver{
 		nextConsumer: nextConsumer,
 		logger:       logger,
-	}
+		telemetry:    telemetry,
+		attributes:   attributes,
+		builderOpts:  builderOpts,
+	}, nil
 }
 
 // Register will register the MetricsServiceServer on the provided grpc Server.
@@ -45,6 +62,10 @@ func (r *Receiver) Register(g *grpc.Server) {
 // StreamMetrics implements the envoy MetricsServiceServer method StreamMetrics.
 // It will consume the envoy prometheus metrics and write them to the nextConsumer.
 func (r *Receiver) StreamMetrics(stream metricsv3.MetricsService_StreamMetricsServer) error {
+	ctx := stream.Context()
+	r.telemetry.streamOpened(ctx)
+	defer r.telemetry.streamClosed(ctx)
+
 	var identifier *metricsv3.StreamMetricsMessage_Identifier
 	var labels map[string]string
 	for {
@@ -56,35 +77,44 @@ func (r *Receiver) StreamMetrics(stream metricsv3.MetricsService_StreamMetricsSe
 			return err
 		}
 		if err := metricsMessage.ValidateAll(); err != nil {
+			cluster := labels[envoyClusterKey]
+			if labels == nil {
+				// the first message of the stream failed, its identifier is the only source of the cluster.
+				cluster = metricsMessage.GetIdentifier().GetNode().GetCluster()
+			}
+			r.telemetry.validationFailed(ctx, cluster)
 			r.logger.Error("failed to validate metric stream", zap.String("error", err.Error()))
 			return err
 		}
 
 		if identifier == nil {
 			identifier = metricsMessage.GetIdentifier()
-
-			labels = map[string]string{
-				"envoy.cluster": identifier.GetNode().GetCluster(), // envoy.cluster is the service name in Consul
-				"node.id":       identifier.GetNode().GetId(),      // node.id delineate proxies
-			}
-
-			fields := identifier.GetNode().GetMetadata().AsMap()
-			setIfExists(labels, fields, namespaceKey)
-			setIfExists(labels, fields, partitionKey)
+			labels = r.attributes.labels(identifier.GetNode())
 		}
 
 		metrics := metricsMessage.GetEnvoyMetrics()
 
-		otlpMetrics := translateMetrics(labels, metrics)
-		err = r.nextConsumer.ConsumeMetrics(stream.Context(), otlpMetrics)
+		otlpMetrics, dropped := translateMetrics(labels, metrics, r.builderOpts...)
+		r.telemetry.messageReceived(ctx, labels[envoyClusterKey], otlpMetrics.DataPointCount())
+		r.telemetry.familiesDropped(ctx, dropped)
+
+		err = r.nextConsumer.ConsumeMetrics(ctx, otlpMetrics)
 		if err != nil {
+			r.telemetry.consumeFailed(ctx, labels[envoyClusterKey])
 			return err
 		}
 	}
 }
 
-func translateMetrics(resourceLabels map[string]string, envoyMetrics []*prompb.MetricFamily) pmetric.Metrics {
-	b := prometheus.NewBuilder(resourceLabels)
+// translateMetrics translates the envoy metric families to otlp. It also returns the number of families
+// that were dropped by type since their type is not supported.
+func translateMetrics(
+	resourceLabels map[string]string,
+	envoyMetrics []*prompb.MetricFamily,
+	builderOpts ...prometheus.Option,
+) (pmetric.Metrics, map[prompb.MetricType]int64) {
+	b := prometheus.NewBuilder(resourceLabels, builderOpts...)
+	dropped := make(map[prompb.MetricType]int64)
 	for _, metric := range envoyMetrics {
 		switch metric.GetType() {
 		case prompb.MetricType_COUNTER:
@@ -93,20 +123,12 @@ func translateMetrics(resourceLabels map[string]string, envoyMetrics []*prompb.M
 			b.AddGauge(metric)
 		case prompb.MetricType_HISTOGRAM:
 			b.AddHistogram(metric)
-		case prompb.MetricType_GAUGE_HISTOGRAM:
-		case prompb.MetricType_SUMMARY:
-		case prompb.MetricType_UNTYPED:
-			// do nothing
+		case prompb.MetricType_GAUGE_HISTOGRAM,
+			prompb.MetricType_SUMMARY,
+			prompb.MetricType_UNTYPED:
+			dropped[metric.GetType()]++
 		}
 	}
 
-	return b.Build()
-}
-
-func setIfExists(labels map[string]string, fields map[string]interface{}, key string) {
-	if v, ok := fields[key]; ok {
-		if s, ok := v.(string); ok {
-			labels[key] = s
-		}
-	}
+	return b.Build(), dropped
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/xhhuango/json"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/metric/noop"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -28,7 +29,8 @@ import (
 
 func TestReceiver_StreamMetrics(t *testing.T) {
 	metricSink := new(consumertest.MetricsSink)
-	receiver := New(metricSink, zap.NewNop())
+	receiver, err := New(metricSink, zap.NewNop(), noop.NewMeterProvider(), ResourceAttributes{})
+	must.NoError(t, err)
 	port := portal.New(t).One()
 
 	addr := fmt.Sprintf("127.0.0.1:%d", port)
@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metrics
+
+import (
+	"fmt"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+)
+
+const (
+	nodeIDKey = "node.id"
+
+	// LocalityRegion is the key of the region of an envoy node's locality.
+	LocalityRegion = "region"
+	// LocalityZone is the key of the zone of an envoy node's locality.
+	LocalityZone = "zone"
+	// LocalitySubZone is the key of the sub zone of an envoy node's locality.
+	LocalitySubZone = "sub_zone"
+)
+
+// ResourceAttributes maps the properties of the envoy node streaming metrics to the resource attributes of its
+// metrics. The envoy.cluster and node.id attributes are always set.
+type ResourceAttributes struct {
+	// NodeMetadata maps node metadata keys to the attribute their string value is set to.
+	NodeMetadata map[string]string
+	// Locality maps the LocalityRegion, LocalityZone and LocalitySubZone keys to the attribute their value is set to.
+	Locality map[string]string
+	// BuildVersion is the attribute the node's user_agent_build_version is set to. It is not set when empty.
+	BuildVersion string
+}
+
+// IsReserved returns true for the envoy.cluster and node.id resource attributes, which identify the node streaming
+// metrics and can't be set to its other properties.
+func IsReserved(attribute string) bool {
+	return attribute == envoyClusterKey || attribute == nodeIDKey
+}
+
+// labels returns the resource attributes of the metrics streamed by node. Properties the node doesn't have, or
+// that are empty, are skipped.
+func (a ResourceAttributes) labels(node *corev3.Node) map[string]string {
+	labels := map[string]string{
+		envoyClusterKey: node.GetCluster(), // envoy.cluster is the service name in Consul
+		nodeIDKey:       node.GetId(),      // node.id delineate proxies
+	}
+
+	fields := node.GetMetadata().AsMap()
+	for key, attribute := range a.NodeMetadata {
+		if v, ok := fields[key].(string); ok && v != "" {
+			labels[attribute] = v
+		}
+	}
+
+	locality := map[string]string{
+		LocalityRegion:  node.GetLocality().GetRegion(),
+		LocalityZone:    node.GetLocality().GetZone(),
+		LocalitySubZone: node.GetLocality().GetSubZone(),
+	}
+	for key, attribute := range a.Locality {
+		if v := locality[key]; v != "" {
+			labels[attribute] = v
+		}
+	}
+
+	if a.BuildVersion != "" {
+		if v := buildVersion(node); v != "" {
+			labels[a.BuildVersion] = v
+		}
+	}
+
+	return labels
+}
+
+// buildVersion formats the node's user_agent_build_version as major.minor.patch. It falls back to the free-form
+// user_agent_version.
+func buildVersion(node *corev3.Node) string {
+	if v := node.GetUserAgentBuildVersion().GetVersion(); v != nil {
+		return fmt.Sprintf("%d.%d.%d", v.GetMajorNumber(), v.GetMinorNumber(), v.GetPatch())
+	}
+	return node.GetUserAgentVersion()
+}
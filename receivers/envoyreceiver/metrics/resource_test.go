@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metrics
+
+import (
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/shoenig/test/must"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestResourceAttributes_labels(t *testing.T) {
+	metadata, err := structpb.NewStruct(map[string]any{
+		"namespace":  "default",
+		"datacenter": "dc1",
+		"service":    "",
+		"port":       8080,
+	})
+	must.NoError(t, err)
+
+	node := &corev3.Node{
+		Id:       "web-sidecar-proxy",
+		Cluster:  "web",
+		Metadata: metadata,
+		Locality: &corev3.Locality{
+			Region: "us-east-1",
+			Zone:   "us-east-1a",
+		},
+		UserAgentVersionType: &corev3.Node_UserAgentBuildVersion{
+			UserAgentBuildVersion: &corev3.BuildVersion{
+				Version: &typev3.SemanticVersion{MajorNumber: 1, MinorNumber: 28, Patch: 2},
+			},
+		},
+	}
+
+	testcases := map[string]struct {
+		attributes ResourceAttributes
+		expect     map[string]string
+	}{
+		"none": {
+			expect: map[string]string{
+				"envoy.cluster": "web",
+				"node.id":       "web-sidecar-proxy",
+			},
+		},
+		"all": {
+			attributes: ResourceAttributes{
+				NodeMetadata: map[string]string{
+					"namespace":  "namespace",
+					"datacenter": "consul.datacenter",
+					"service":    "service.name",
+					"port":       "port",
+					"missing":    "missing",
+				},
+				Locality: map[string]string{
+					LocalityRegion:  "cloud.region",
+					LocalityZone:    "cloud.availability_zone",
+					LocalitySubZone: "envoy.sub_zone",
+				},
+				BuildVersion: "envoy.version",
+			},
+			expect: map[string]string{
+				"envoy.cluster":           "web",
+				"node.id":                 "web-sidecar-proxy",
+				"namespace":               "default",
+				"consul.datacenter":       "dc1",
+				"cloud.region":            "us-east-1",
+				"cloud.availability_zone": "us-east-1a",
+				"envoy.version":           "1.28.2",
+			},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			must.Eq(t, tc.expect, tc.attributes.labels(node))
+		})
+	}
+}
+
+func TestResourceAttributes_labelsUserAgentVersion(t *testing.T) {
+	node := &corev3.Node{
+		UserAgentVersionType: &corev3.Node_UserAgentVersion{UserAgentVersion: "1.28.2-dev"},
+	}
+
+	labels := ResourceAttributes{BuildVersion: "envoy.version"}.labels(node)
+	must.Eq(t, "1.28.2-dev", labels["envoy.version"])
+}
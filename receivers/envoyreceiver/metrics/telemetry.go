@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metrics
+
+import (
+	"context"
+
+	prompb "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
+)
+
+const (
+	// scopeName is the instrumentation scope of the receiver's own metrics.
+	scopeName = "github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver"
+
+	envoyClusterKey = "envoy.cluster"
+	typeKey         = "type"
+)
+
+// receiverTelemetry records metrics about the envoy metric streams the receiver is serving.
+type receiverTelemetry struct {
+	activeStreams      metric.Int64UpDownCounter
+	streamsOpened      metric.Int64Counter
+	streamsClosed      metric.Int64Counter
+	messagesReceived   metric.Int64Counter
+	dataPointsReceived metric.Int64Counter
+	validationFailures metric.Int64Counter
+	droppedFamilies    metric.Int64Counter
+	consumeErrors      metric.Int64Counter
+}
+
+func newReceiverTelemetry(meterProvider metric.MeterProvider) (*receiverTelemetry, error) {
+	meter := meterProvider.Meter(scopeName)
+
+	var errs, err error
+	t := &receiverTelemetry{}
+
+	t.activeStreams, err = meter.Int64UpDownCounter("envoyreceiver_active_streams",
+		metric.WithDescription("Number of envoy metric streams currently open"))
+	errs = multierr.Append(errs, err)
+
+	t.streamsOpened, err = meter.Int64Counter("envoyreceiver_streams_opened",
+		metric.WithDescription("Number of envoy metric streams opened"))
+	errs = multierr.Append(errs, err)
+
+	t.streamsClosed, err = meter.Int64Counter("envoyreceiver_streams_closed",
+		metric.WithDescription("Number of envoy metric streams closed"))
+	errs = multierr.Append(errs, err)
+
+	t.messagesReceived, err = meter.Int64Counter("envoyreceiver_messages_received",
+		metric.WithDescription("Number of stream messages received per envoy cluster"))
+	errs = multierr.Append(errs, err)
+
+	t.dataPointsReceived, err = meter.Int64Counter("envoyreceiver_data_points_received",
+		metric.WithDescription("Number of metric data points received per envoy cluster"))
+	errs = multierr.Append(errs, err)
+
+	t.validationFailures, err = meter.Int64Counter("envoyreceiver_validation_failures",
+		metric.WithDescription("Number of stream messages that failed validation"))
+	errs = multierr.Append(errs, err)
+
+	t.droppedFamilies, err = meter.Int64Counter("envoyreceiver_dropped_metric_families",
+		metric.WithDescription("Number of metric families dropped because their type is not supported"))
+	errs = multierr.Append(errs, err)
+
+	t.consumeErrors, err = meter.Int64Counter("envoyreceiver_consume_errors",
+		metric.WithDescription("Number of errors returned by the next consumer"))
+	errs = multierr.Append(errs, err)
+
+	return t, errs
+}
+
+func (t *receiverTelemetry) streamOpened(ctx context.Context) {
+	t.activeStreams.Add(ctx, 1)
+	t.streamsOpened.Add(ctx, 1)
+}
+
+func (t *receiverTelemetry) streamClosed(ctx context.Context) {
+	t.activeStreams.Add(ctx, -1)
+	t.streamsClosed.Add(ctx, 1)
+}
+
+func (t *receiverTelemetry) messageReceived(ctx context.Context, cluster string, dataPoints int) {
+	attrs := metric.WithAttributes(attribute.String(envoyClusterKey, cluster))
+	t.messagesReceived.Add(ctx, 1, attrs)
+	t.dataPointsReceived.Add(ctx, int64(dataPoints), attrs)
+}
+
+func (t *receiverTelemetry) validationFailed(ctx context.Context, cluster string) {
+	t.validationFailures.Add(ctx, 1, metric.WithAttributes(attribute.String(envoyClusterKey, cluster)))
+}
+
+func (t *receiverTelemetry) familiesDropped(ctx context.Context, dropped map[prompb.MetricType]int64) {
+	for metricType, count := range dropped {
+		t.droppedFamilies.Add(ctx, count, metric.WithAttributes(attribute.String(typeKey, metricType.String())))
+	}
+}
+
+func (t *receiverTelemetry) consumeFailed(ctx context.Context, cluster string) {
+	t.consumeErrors.Add(ctx, 1, metric.WithAttributes(attribute.String(envoyClusterKey, cluster)))
+}
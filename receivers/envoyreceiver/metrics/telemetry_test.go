@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	metricsv3 "github.com/envoyproxy/go-control-plane/envoy/service/metrics/v3"
+	prompb "github.com/prometheus/client_model/go"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReceiver_Telemetry(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	receiver, err := New(new(consumertest.MetricsSink), zap.NewNop(),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), ResourceAttributes{})
+	must.NoError(t, err)
+
+	client := serveReceiver(t, receiver)
+	stream, err := client.StreamMetrics(context.Background())
+	must.NoError(t, err)
+
+	families := []*prompb.MetricFamily{
+		{
+			Name:   proto.String("envoy_cluster_upstream_rq_total"),
+			Type:   prompb.MetricType_COUNTER.Enum(),
+			Metric: []*prompb.Metric{{Counter: &prompb.Counter{Value: proto.Float64(1)}}},
+		},
+		{
+			Name:   proto.String("envoy_summary"),
+			Type:   prompb.MetricType_SUMMARY.Enum(),
+			Metric: []*prompb.Metric{{Summary: &prompb.Summary{}}},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		must.NoError(t, stream.Send(&metricsv3.StreamMetricsMessage{
+			Identifier:   &metricsv3.StreamMetricsMessage_Identifier{Node: &corev3.Node{Id: "web-sidecar", Cluster: "web"}},
+			EnvoyMetrics: families,
+		}))
+	}
+	_, err = stream.CloseAndRecv()
+	must.NoError(t, err)
+
+	// the stream is closed on the server after the response is sent.
+	must.Wait(t, wait.InitialSuccess(wait.BoolFunc(func() bool {
+		return sumValue(t, collect(t, reader), "envoyreceiver_active_streams", *attribute.EmptySet()) == 0
+	}), wait.Timeout(time.Second)))
+
+	rm := collect(t, reader)
+	web := attribute.NewSet(attribute.String(envoyClusterKey, "web"))
+	must.Eq(t, 1, sumValue(t, rm, "envoyreceiver_streams_opened", *attribute.EmptySet()))
+	must.Eq(t, 1, sumValue(t, rm, "envoyreceiver_streams_closed", *attribute.EmptySet()))
+	must.Eq(t, 2, sumValue(t, rm, "envoyreceiver_messages_received", web))
+	must.Eq(t, 2, sumValue(t, rm, "envoyreceiver_data_points_received", web))
+	must.Eq(t, 2, sumValue(t, rm, "envoyreceiver_dropped_metric_families",
+		attribute.NewSet(attribute.String(typeKey, prompb.MetricType_SUMMARY.String()))))
+}
+
+func TestReceiver_TelemetryConsumeError(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	receiver, err := New(consumertest.NewErr(errors.New("boom")), zap.NewNop(),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), ResourceAttributes{})
+	must.NoError(t, err)
+
+	client := serveReceiver(t, receiver)
+	stream, err := client.StreamMetrics(context.Background())
+	must.NoError(t, err)
+	must.NoError(t, stream.Send(&metricsv3.StreamMetricsMessage{
+		Identifier: &metricsv3.StreamMetricsMessage_Identifier{Node: &corev3.Node{Id: "api-sidecar", Cluster: "api"}},
+	}))
+	_, err = stream.CloseAndRecv()
+	must.Error(t, err)
+
+	rm := collect(t, reader)
+	must.Eq(t, 1, sumValue(t, rm, "envoyreceiver_consume_errors",
+		attribute.NewSet(attribute.String(envoyClusterKey, "api"))))
+}
+
+func TestReceiver_TelemetryValidationError(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	receiver, err := New(new(consumertest.MetricsSink), zap.NewNop(),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)), ResourceAttributes{})
+	must.NoError(t, err)
+
+	client := serveReceiver(t, receiver)
+	stream, err := client.StreamMetrics(context.Background())
+	must.NoError(t, err)
+
+	// the first message fails validation, an address must be set, so the cluster comes from its identifier.
+	must.NoError(t, stream.Send(&metricsv3.StreamMetricsMessage{
+		Identifier: &metricsv3.StreamMetricsMessage_Identifier{Node: &corev3.Node{
+			Id:                 "api-sidecar",
+			Cluster:            "api",
+			ListeningAddresses: []*corev3.Address{{}},
+		}},
+	}))
+	_, err = stream.CloseAndRecv()
+	must.Error(t, err)
+
+	rm := collect(t, reader)
+	must.Eq(t, 1, sumValue(t, rm, "envoyreceiver_validation_failures",
+		attribute.NewSet(attribute.String(envoyClusterKey, "api"))))
+}
+
+func serveReceiver(t *testing.T, receiver *Receiver) metricsv3.MetricsServiceClient {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+
+	s := grpc.NewServer()
+	receiver.Register(s)
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	must.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return metricsv3.NewMetricsServiceClient(conn)
+}
+
+func collect(t *testing.T, reader sdkmetric.Reader) metricdata.ResourceMetrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	must.NoError(t, reader.Collect(context.Background(), &rm))
+	return rm
+}
+
+// sumValue returns the value of the data point with the attributes of the named int64 sum.
+func sumValue(t *testing.T, rm metricdata.ResourceMetrics, name string, attrs attribute.Set) int64 {
+	t.Helper()
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			must.True(t, ok, must.Sprintf("%s is not an int64 sum", name))
+			for _, dp := range sum.DataPoints {
+				if dp.Attributes.Equals(&attrs) {
+					return dp.Value
+				}
+			}
+		}
+	}
+	t.Fatalf("no %s data point with attributes %v", name, attrs.ToSlice())
+	return 0
+}
+
+func Test_translateMetrics_Dropped(t *testing.T) {
+	_, dropped := translateMetrics(nil, []*prompb.MetricFamily{
+		{Type: prompb.MetricType_SUMMARY.Enum()},
+		{Type: prompb.MetricType_UNTYPED.Enum()},
+		{Type: prompb.MetricType_UNTYPED.Enum()},
+		{Type: prompb.MetricType_GAUGE.Enum()},
+	})
+	must.MapEq(t, map[prompb.MetricType]int64{
+		prompb.MetricType_SUMMARY: 1,
+		prompb.MetricType_UNTYPED: 2,
+	}, dropped)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envoyreceiver
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul-telemetry-collector/receivers/envoyreceiver/metrics"
+)
+
+// ResourceAttributesConfig maps the properties of the envoy node streaming metrics to the resource attributes
+// of its metrics, in addition to envoy.cluster and node.id which are always set. An empty attribute name keeps
+// the name of the property.
+type ResourceAttributesConfig struct {
+	// NodeMetadata maps node metadata keys to attribute names. Only string values are set.
+	NodeMetadata map[string]string `mapstructure:"node_metadata,omitempty"`
+	// Locality maps the region, zone and sub_zone of the node locality to attribute names.
+	Locality map[string]string `mapstructure:"locality,omitempty"`
+	// BuildVersion is the attribute name the node's user_agent_build_version is set to. It is not set when empty.
+	BuildVersion string `mapstructure:"build_version"`
+}
+
+// defaultNodeMetadata are the Consul node metadata keys set as resource attributes by default.
+var defaultNodeMetadata = map[string]string{
+	"namespace": "",
+	"partition": "",
+}
+
+// Validate checks the locality fields are known and that no property is set to the envoy.cluster or node.id
+// attributes.
+func (c *ResourceAttributesConfig) Validate() error {
+	for key := range c.Locality {
+		switch key {
+		case metrics.LocalityRegion, metrics.LocalityZone, metrics.LocalitySubZone:
+		default:
+			return fmt.Errorf("unknown locality field %q, expected one of %s, %s or %s", key,
+				metrics.LocalityRegion, metrics.LocalityZone, metrics.LocalitySubZone)
+		}
+	}
+
+	for property, mapping := range map[string]map[string]string{
+		"node_metadata": c.NodeMetadata,
+		"locality":      c.Locality,
+	} {
+		for key, name := range withDefaultNames(mapping) {
+			if metrics.IsReserved(name) {
+				return fmt.Errorf("%s %q can't be set to the reserved attribute %q", property, key, name)
+			}
+		}
+	}
+	if metrics.IsReserved(c.BuildVersion) {
+		return fmt.Errorf("build_version can't be set to the reserved attribute %q", c.BuildVersion)
+	}
+	return nil
+}
+
+func (c *ResourceAttributesConfig) attributes() metrics.ResourceAttributes {
+	return metrics.ResourceAttributes{
+		NodeMetadata: withDefaultNames(c.NodeMetadata),
+		Locality:     withDefaultNames(c.Locality),
+		BuildVersion: c.BuildVersion,
+	}
+}
+
+// withDefaultNames returns a copy of the mapping where empty attribute names are replaced by their key.
+func withDefaultNames(mapping map[string]string) map[string]string {
+	names := make(map[string]string, len(mapping))
+	for key, name := range mapping {
+		if name == "" {
+			name = key
+		}
+		names[key] = name
+	}
+	return names
+}
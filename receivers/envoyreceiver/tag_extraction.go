@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envoyreceiver
+
+import (
+	"github.com/hashicorp/consul-telemetry-collector/internal/translator/otlp/prometheus"
+)
+
+// TagExtractionConfig configures extracting the dimensions envoy embeds in stat names, like the cluster name in
+// cluster.<cluster_name>.upstream_rq_<response_code>, into data point attributes.
+type TagExtractionConfig struct {
+	// DisableDefaults skips envoy's default tag extraction regexes so only Extractors apply.
+	DisableDefaults bool `mapstructure:"disable_defaults"`
+	// Extractors are applied in addition to envoy's default tag extraction regexes.
+	Extractors []TagExtractorConfig `mapstructure:"extractors"`
+}
+
+// TagExtractorConfig is a user supplied tag extractor. As in envoy the first capture group of the regex is
+// removed from the stat name and the attribute value is the second capture group, or the first if there is
+// only one.
+type TagExtractorConfig struct {
+	// Name is the attribute name set to the extracted value.
+	Name string `mapstructure:"name"`
+	// Regex extracts the value from the stat name.
+	Regex string `mapstructure:"regex"`
+}
+
+func (c *TagExtractionConfig) extractors() ([]prometheus.TagExtractor, error) {
+	var extractors []prometheus.TagExtractor
+	if !c.DisableDefaults {
+		extractors = prometheus.DefaultTagExtractors()
+	}
+
+	for _, e := range c.Extractors {
+		extractor, err := prometheus.NewTagExtractor(e.Name, e.Regex)
+		if err != nil {
+			return nil, err
+		}
+		extractors = append(extractors, extractor)
+	}
+	return extractors, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/portal"
+	"github.com/shoenig/test/wait"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/hashicorp/consul-telemetry-collector/internal/hcp"
+	"github.com/hashicorp/consul-telemetry-collector/internal/hcp/fakehcp"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel"
+	"github.com/hashicorp/consul-telemetry-collector/internal/otel/config/helpers/processors"
+)
+
+const hcpResourceURL = "organization/00000000-0000-0000-0000-000000000000/project/" +
+	"00000000-0000-0000-0000-000000000001/hashicorp.consul.cluster/otel-cluster"
+
+func Test_HCP(t *testing.T) {
+	// only every other generated metric is forwarded to HCP.
+	include := "^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{11}[02468ace]"
+	server, err := fakehcp.Start("127.0.0.1:0", fakehcp.Config{
+		ClientID:     "cid",
+		ClientSecret: "csec",
+		IncludeList:  []string{include},
+		Labels:       map[string]string{"cluster": "otel-cluster"},
+	})
+	must.NoError(t, err)
+	t.Cleanup(server.Close)
+	for k, v := range server.Env() {
+		t.Setenv(k, v)
+	}
+
+	client, err := hcp.New(&hcp.Params{ClientID: "cid", ClientSecret: "csec", ResourceURL: hcpResourceURL})
+	must.NoError(t, err)
+
+	envoyPort := portal.New(t).One()
+	collector, err := otel.NewCollector(otel.CollectorCfg{
+		ClientID:     "cid",
+		ClientSecret: "csec",
+		Client:       client,
+		ResourceID:   hcpResourceURL,
+		MetricsPort:  portal.New(t).One(),
+		BatchTimeout: time.Second,
+		EnvoyPort:    envoyPort,
+	})
+	must.NoError(t, err)
+	ctx := context.Background()
+	go func() { must.NoError(t, collector.Run(ctx)) }()
+	t.Cleanup(collector.Shutdown)
+
+	generateMetrics(t, envoyPort, 1, 30)
+
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool { return len(server.Exports()) > 0 }),
+		wait.Timeout(10*time.Second),
+		wait.Gap(100*time.Millisecond),
+	))
+
+	filter := regexp.MustCompile(include)
+	forwarded := 0
+	for _, e := range server.Exports() {
+		must.Eq(t, hcpResourceURL, e.ResourceID)
+		rms := e.Metrics.ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			rm := rms.At(i)
+			cluster, ok := rm.Resource().Attributes().Get("cluster")
+			must.True(t, ok)
+			must.Eq(t, "otel-cluster", cluster.Str())
+			forEachMetric(rm, func(m pmetric.Metric) {
+				must.RegexMatch(t, filter, m.Name())
+				forwarded++
+			})
+		}
+	}
+	must.Positive(t, forwarded)
+}
+
+func Test_HCPWorkloadIdentity(t *testing.T) {
+	const provider = "iam/project/00000000-0000-0000-0000-000000000001/service-principal/collector/" +
+		"workload-identity-provider/k8s"
+	server, err := fakehcp.Start("127.0.0.1:0", fakehcp.Config{
+		WorkloadIdentityProvider: provider,
+		WorkloadIdentityToken:    "jwt",
+		Labels:                   map[string]string{"cluster": "otel-cluster"},
+	})
+	must.NoError(t, err)
+	t.Cleanup(server.Close)
+	for k, v := range server.Env() {
+		t.Setenv(k, v)
+	}
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	must.NoError(t, os.WriteFile(tokenFile, []byte("jwt"), 0o600))
+	workloadIdentity := hcp.WorkloadIdentity{ProviderResourceName: provider, TokenFile: tokenFile}
+
+	client, err := hcp.New(&hcp.Params{WorkloadIdentity: workloadIdentity, ResourceURL: hcpResourceURL})
+	must.NoError(t, err)
+
+	// without a client secret to derive the hash key from, the HCP redaction is configured.
+	redaction := processors.DefaultHCPRedaction("test-hash-key")
+	envoyPort := portal.New(t).One()
+	collector, err := otel.NewCollector(otel.CollectorCfg{
+		WorkloadIdentity: workloadIdentity,
+		Client:           client,
+		ResourceID:       hcpResourceURL,
+		MetricsPort:      portal.New(t).One(),
+		BatchTimeout:     time.Second,
+		EnvoyPort:        envoyPort,
+		HCPRedaction:     &redaction,
+	})
+	must.NoError(t, err)
+	ctx := context.Background()
+	go func() { must.NoError(t, collector.Run(ctx)) }()
+	t.Cleanup(collector.Shutdown)
+
+	generateMetrics(t, envoyPort, 1, 30)
+
+	// the exporter authenticates with the exchanged token.
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool { return len(server.Exports()) > 0 }),
+		wait.Timeout(10*time.Second),
+		wait.Gap(100*time.Millisecond),
+	))
+	for _, e := range server.Exports() {
+		must.Eq(t, hcpResourceURL, e.ResourceID)
+	}
+}
+
+func forEachMetric(rm pmetric.ResourceMetrics, f func(pmetric.Metric)) {
+	sms := rm.ScopeMetrics()
+	for i := 0; i < sms.Len(); i++ {
+		metrics := sms.At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			f(metrics.At(j))
+		}
+	}
+}